@@ -0,0 +1,98 @@
+// Package argo provides an OpenTelemetry detector for detecting Argo Workflows
+// resources.
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	workflowNameEnv = "ARGO_WORKFLOW_NAME"
+	nodeIDEnv       = "ARGO_NODE_ID"
+	templateEnv     = "ARGO_TEMPLATE"
+)
+
+const (
+	workflowNameKey = attribute.Key("argo.workflow.name")
+	nodeIDKey       = attribute.Key("argo.workflow.node.id")
+	templateKey     = attribute.Key("argo.workflow.template")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type argoDetectorUtils struct{}
+
+func (utils *argoDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	workflowName, _ := detector.utils.lookupEnv(workflowNameEnv)
+	if workflowName == "" {
+		// Not an Argo Workflows step
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		workflowNameKey.String(workflowName),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			nodeIDEnv,
+			nodeIDKey.String,
+		},
+		{
+			templateEnv,
+			func(v string) attribute.KeyValue {
+				return templateKey.String(templateName(v))
+			},
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// templateName returns the name of the template. The executor sets the
+// environment variable to the whole template as JSON, which is too large to
+// use as an attribute value, so only the name is used if it can be parsed.
+func templateName(v string) string {
+	var template struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.Unmarshal([]byte(v), &template); err != nil || template.Name == "" {
+		return v
+	}
+
+	return template.Name
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect Argo
+// Workflows resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(argoDetectorUtils),
+	}
+}
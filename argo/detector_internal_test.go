@@ -0,0 +1,81 @@
+package argo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestArgo(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "json",
+			template: `{"name":"build","inputs":{},"outputs":{},"metadata":{},"container":{"name":"","image":"golang:1.25"}}`,
+			expected: "build",
+		},
+		{
+			name:     "plain",
+			template: "build",
+			expected: "build",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", workflowNameEnv).Return("ci-abc12", true).Once()
+			utils.On("lookupEnv", nodeIDEnv).Return("ci-abc12-1234567890", true).Once()
+			utils.On("lookupEnv", templateEnv).Return(table.template, true).Once()
+
+			argoResourceDetector := resourceDetector{utils: utils}
+
+			r, err := argoResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				workflowNameKey.String("ci-abc12"),
+				nodeIDKey.String("ci-abc12-1234567890"),
+				templateKey.String(table.expected),
+			}...), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotArgo(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", workflowNameEnv).Return("", false).Once()
+
+	argoResourceDetector := resourceDetector{utils: utils}
+
+	r, err := argoResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
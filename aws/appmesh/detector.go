@@ -0,0 +1,79 @@
+// Package appmesh provides an OpenTelemetry detector for detecting AWS App
+// Mesh virtual node resources, as configured for an Envoy sidecar.
+package appmesh
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	virtualNodeNameEnv = "APPMESH_VIRTUAL_NODE_NAME"
+	resourceARNEnv     = "APPMESH_RESOURCE_ARN"
+)
+
+const (
+	virtualNodeNameKey = attribute.Key("aws.appmesh.virtual_node.name")
+	resourceARNKey     = attribute.Key("aws.appmesh.resource.arn")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type appMeshDetectorUtils struct{}
+
+func (utils *appMeshDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	var attributes []attribute.KeyValue
+
+	// Older Envoy images use the virtual node name, newer ones the ARN of
+	// either a virtual node or virtual gateway
+	for _, s := range []struct {
+		env string
+		key attribute.Key
+	}{
+		{
+			virtualNodeNameEnv,
+			virtualNodeNameKey,
+		},
+		{
+			resourceARNEnv,
+			resourceARNKey,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.key.String(v))
+		}
+	}
+
+	if len(attributes) == 0 {
+		// Not an App Mesh sidecar
+		return resource.Empty(), nil
+	}
+
+	attributes = append([]attribute.KeyValue{semconv.CloudProviderAWS}, attributes...)
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS App
+// Mesh virtual node resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(appMeshDetectorUtils),
+	}
+}
@@ -0,0 +1,82 @@
+package appmesh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestAppMesh(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name            string
+		virtualNodeName string
+		resourceARN     string
+		expected        *resource.Resource
+	}{
+		{
+			name:            "virtual node name",
+			virtualNodeName: "mesh/my-mesh/virtualNode/my-node",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				virtualNodeNameKey.String("mesh/my-mesh/virtualNode/my-node"),
+			}...),
+		},
+		{
+			name:        "resource ARN",
+			resourceARN: "arn:aws:appmesh:eu-west-1:0123456789012:mesh/my-mesh/virtualNode/my-node",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				resourceARNKey.String("arn:aws:appmesh:eu-west-1:0123456789012:mesh/my-mesh/virtualNode/my-node"),
+			}...),
+		},
+		{
+			name:            "both",
+			virtualNodeName: "mesh/my-mesh/virtualNode/my-node",
+			resourceARN:     "arn:aws:appmesh:eu-west-1:0123456789012:mesh/my-mesh/virtualNode/my-node",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				virtualNodeNameKey.String("mesh/my-mesh/virtualNode/my-node"),
+				resourceARNKey.String("arn:aws:appmesh:eu-west-1:0123456789012:mesh/my-mesh/virtualNode/my-node"),
+			}...),
+		},
+		{
+			name:     "not app mesh",
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", virtualNodeNameEnv).Return(table.virtualNodeName, table.virtualNodeName != "").Once()
+			utils.On("lookupEnv", resourceARNEnv).Return(table.resourceARN, table.resourceARN != "").Once()
+
+			appMeshResourceDetector := resourceDetector{utils: utils}
+
+			r, err := appMeshResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package batch provides an OpenTelemetry detector for detecting AWS Batch
+// job resources.
+package batch
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	jobIDEnv              = "AWS_BATCH_JOB_ID"
+	jobAttemptEnv         = "AWS_BATCH_JOB_ATTEMPT"
+	computeEnvironmentEnv = "AWS_BATCH_CE_NAME"
+	jobQueueEnv           = "AWS_BATCH_JQ_NAME"
+)
+
+const (
+	jobIDKey              = attribute.Key("aws.batch.job.id")
+	jobAttemptKey         = attribute.Key("aws.batch.job.attempt")
+	computeEnvironmentKey = attribute.Key("aws.batch.compute_environment")
+	jobQueueKey           = attribute.Key("aws.batch.job_queue")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type batchDetectorUtils struct{}
+
+func (utils *batchDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	if v, _ := detector.utils.lookupEnv(jobIDEnv); v == "" {
+		// Not an AWS Batch job
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+	}
+
+	for _, s := range []struct {
+		env string
+		key attribute.Key
+	}{
+		{
+			jobIDEnv,
+			jobIDKey,
+		},
+		{
+			jobAttemptEnv,
+			jobAttemptKey,
+		},
+		{
+			computeEnvironmentEnv,
+			computeEnvironmentKey,
+		},
+		{
+			jobQueueEnv,
+			jobQueueKey,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.key.String(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS Batch
+// job resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(batchDetectorUtils),
+	}
+}
@@ -0,0 +1,61 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", jobIDEnv).Return("9f1c2b3a-4d5e-6f70-8192-a3b4c5d6e7f8", true).Twice()
+	utils.On("lookupEnv", jobAttemptEnv).Return("2", true).Once()
+	utils.On("lookupEnv", computeEnvironmentEnv).Return("spot-ce", true).Once()
+	utils.On("lookupEnv", jobQueueEnv).Return("high-priority", true).Once()
+
+	batchResourceDetector := resourceDetector{utils: utils}
+
+	r, err := batchResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		jobIDKey.String("9f1c2b3a-4d5e-6f70-8192-a3b4c5d6e7f8"),
+		jobAttemptKey.String("2"),
+		computeEnvironmentKey.String("spot-ce"),
+		jobQueueKey.String("high-priority"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotBatch(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", jobIDEnv).Return("", false).Once()
+
+	batchResourceDetector := resourceDetector{utils: utils}
+
+	r, err := batchResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+// Package beanstalk provides an OpenTelemetry detector for detecting AWS
+// Elastic Beanstalk resources.
+package beanstalk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	configFile = "var/elasticbeanstalk/xray/environment.conf"
+	regionEnv  = "AWS_REGION"
+)
+
+type environmentConfig struct {
+	DeploymentID    int    `json:"deployment_id"`    //nolint:tagliatelle
+	VersionLabel    string `json:"version_label"`    //nolint:tagliatelle
+	EnvironmentName string `json:"environment_name"` //nolint:tagliatelle
+}
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type beanstalkDetectorUtils struct{}
+
+func (utils *beanstalkDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+	fsys  fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	b, err := fs.ReadFile(detector.fsys, configFile)
+	if err != nil {
+		// Not on Elastic Beanstalk
+		if errors.Is(err, fs.ErrNotExist) {
+			return resource.Empty(), nil
+		}
+
+		return nil, fmt.Errorf("error reading Elastic Beanstalk config: %w", err)
+	}
+
+	config := new(environmentConfig)
+	if err := json.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("error parsing Elastic Beanstalk config: %w", err)
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSElasticBeanstalk,
+		semconv.ServiceInstanceID(strconv.Itoa(config.DeploymentID)),
+	}
+
+	for _, s := range []struct {
+		value string
+		fn    func(string) attribute.KeyValue
+	}{
+		{
+			config.EnvironmentName,
+			semconv.ServiceNamespace,
+		},
+		{
+			config.VersionLabel,
+			semconv.ServiceVersion,
+		},
+	} {
+		if s.value != "" {
+			attributes = append(attributes, s.fn(s.value))
+		}
+	}
+
+	if v, _ := detector.utils.lookupEnv(regionEnv); v != "" {
+		attributes = append(attributes, semconv.CloudRegion(v))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read the Elastic Beanstalk config. It
+// should be rooted at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+func newResourceDetector(utils detectorUtils, options ...Option) *resourceDetector {
+	detector := &resourceDetector{
+		utils: utils,
+		fsys:  os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS
+// Elastic Beanstalk resources.
+func NewResourceDetector(options ...Option) resource.Detector {
+	return newResourceDetector(new(beanstalkDetectorUtils), options...)
+}
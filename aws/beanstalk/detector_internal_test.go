@@ -0,0 +1,77 @@
+package beanstalk
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestBeanstalk(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", regionEnv).Return("eu-west-1", true).Once()
+
+	beanstalkResourceDetector := newResourceDetector(utils, WithFS(os.DirFS("testdata")))
+
+	r, err := beanstalkResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSElasticBeanstalk,
+		semconv.ServiceInstanceID("23"),
+		semconv.ServiceNamespace("my-env"),
+		semconv.ServiceVersion("app-1a2b3c-261015_101500"),
+		semconv.CloudRegion("eu-west-1"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotBeanstalk(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+
+	beanstalkResourceDetector := newResourceDetector(utils, WithFS(fstest.MapFS{}))
+
+	r, err := beanstalkResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+
+	beanstalkResourceDetector := newResourceDetector(utils, WithFS(fstest.MapFS{
+		configFile: &fstest.MapFile{
+			Data: []byte("not json"),
+		},
+	}))
+
+	_, err := beanstalkResourceDetector.Detect(t.Context())
+	require.Error(t, err)
+
+	utils.AssertExpectations(t)
+}
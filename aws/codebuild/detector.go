@@ -0,0 +1,85 @@
+// Package codebuild provides an OpenTelemetry detector for detecting AWS
+// CodeBuild build resources.
+package codebuild
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	buildIDEnv   = "CODEBUILD_BUILD_ID"
+	buildARNEnv  = "CODEBUILD_BUILD_ARN"
+	initiatorEnv = "CODEBUILD_INITIATOR"
+	regionEnv    = "AWS_REGION"
+)
+
+const (
+	buildARNKey  = attribute.Key("aws.codebuild.build.arn")
+	initiatorKey = attribute.Key("aws.codebuild.initiator")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type codeBuildDetectorUtils struct{}
+
+func (utils *codeBuildDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	buildID, _ := detector.utils.lookupEnv(buildIDEnv)
+	if buildID == "" {
+		// Not a CodeBuild build
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CICDPipelineRunID(buildID),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			buildARNEnv,
+			buildARNKey.String,
+		},
+		{
+			initiatorEnv,
+			initiatorKey.String,
+		},
+		{
+			regionEnv,
+			semconv.CloudRegion,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS
+// CodeBuild build resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(codeBuildDetectorUtils),
+	}
+}
@@ -0,0 +1,61 @@
+package codebuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestCodeBuild(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("my-project:b1e6661e-e4f2-4156-9ab9-82a19EXAMPLE", true).Once()
+	utils.On("lookupEnv", buildARNEnv).Return("arn:aws:codebuild:eu-west-1:0123456789012:build/my-project:b1e6661e-e4f2-4156-9ab9-82a19EXAMPLE", true).Once()
+	utils.On("lookupEnv", initiatorEnv).Return("codepipeline/my-pipeline", true).Once()
+	utils.On("lookupEnv", regionEnv).Return("eu-west-1", true).Once()
+
+	codeBuildResourceDetector := resourceDetector{utils: utils}
+
+	r, err := codeBuildResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CICDPipelineRunID("my-project:b1e6661e-e4f2-4156-9ab9-82a19EXAMPLE"),
+		buildARNKey.String("arn:aws:codebuild:eu-west-1:0123456789012:build/my-project:b1e6661e-e4f2-4156-9ab9-82a19EXAMPLE"),
+		initiatorKey.String("codepipeline/my-pipeline"),
+		semconv.CloudRegion("eu-west-1"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotCodeBuild(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("", false).Once()
+
+	codeBuildResourceDetector := resourceDetector{utils: utils}
+
+	r, err := codeBuildResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
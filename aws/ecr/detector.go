@@ -0,0 +1,76 @@
+// Package ecr provides an OpenTelemetry detector for detecting the Amazon
+// Elastic Container Registry (ECR) used by a build pipeline.
+package ecr
+
+import (
+	"context"
+	"os"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	registryEnv = "AWS_ECR_REGISTRY"
+	regionEnv   = "AWS_REGION"
+)
+
+const registryURLKey = attribute.Key("aws.ecr.registry.url")
+
+// Private registries are named after the account and region, such as
+// "012345678901.dkr.ecr.eu-west-1.amazonaws.com".
+var registryRegexp = regexp.MustCompile(`^(?:https://)?(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?/?$`)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type ecrDetectorUtils struct{}
+
+func (utils *ecrDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	registry, _ := detector.utils.lookupEnv(registryEnv)
+	if registry == "" {
+		// Not pulling from ECR
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		registryURLKey.String(registry),
+	}
+
+	m := registryRegexp.FindStringSubmatch(registry)
+	if m != nil {
+		attributes = append(attributes, semconv.CloudAccountID(m[1]))
+	}
+
+	// Prefer the region from the environment, falling back to the region the
+	// registry is in
+	if v, _ := detector.utils.lookupEnv(regionEnv); v != "" {
+		attributes = append(attributes, semconv.CloudRegion(v))
+	} else if m != nil {
+		attributes = append(attributes, semconv.CloudRegion(m[2]))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Amazon
+// ECR registry resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(ecrDetectorUtils),
+	}
+}
@@ -0,0 +1,97 @@
+package ecr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestECR(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		registry string
+		region   string
+		expected *resource.Resource
+	}{
+		{
+			name:     "private",
+			registry: "012345678901.dkr.ecr.eu-west-1.amazonaws.com",
+			region:   "eu-west-1",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				registryURLKey.String("012345678901.dkr.ecr.eu-west-1.amazonaws.com"),
+				semconv.CloudAccountID("012345678901"),
+				semconv.CloudRegion("eu-west-1"),
+			),
+		},
+		{
+			name:     "region from registry",
+			registry: "https://012345678901.dkr.ecr.cn-north-1.amazonaws.com.cn",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				registryURLKey.String("https://012345678901.dkr.ecr.cn-north-1.amazonaws.com.cn"),
+				semconv.CloudAccountID("012345678901"),
+				semconv.CloudRegion("cn-north-1"),
+			),
+		},
+		{
+			name:     "public",
+			registry: "public.ecr.aws",
+			region:   "us-east-1",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				registryURLKey.String("public.ecr.aws"),
+				semconv.CloudRegion("us-east-1"),
+			),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", registryEnv).Return(table.registry, true).Once()
+			utils.On("lookupEnv", regionEnv).Return(table.region, table.region != "").Once()
+
+			ecrResourceDetector := resourceDetector{utils: utils}
+
+			r, err := ecrResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotECR(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", registryEnv).Return("", false).Once()
+
+	ecrResourceDetector := resourceDetector{utils: utils}
+
+	r, err := ecrResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
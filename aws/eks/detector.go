@@ -1,33 +1,114 @@
+// Package eks provides an OpenTelemetry detector for detecting AWS EKS
+// resources.
+//
+// Detected resources use the schema URL of the semantic conventions version
+// imported by this package, which is kept in step with the version used by
+// the OpenTelemetry SDK so that they can be merged with [resource.Default]
+// and the resources returned by the SDK's own detectors. Merging resources
+// with different non-empty schema URLs fails with
+// [resource.ErrSchemaURLConflict].
 package eks
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/smithy-go"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/rest"
 )
 
+const (
+	// NodeNameEnv is the environment variable that should be populated with
+	// the node name using the downward API, i.e. from the `spec.nodeName`
+	// field. It is only required by [WithRegionFromIMDS] and
+	// [WithComputeType].
+	NodeNameEnv = "NODE_NAME"
+
+	defaultDialTimeout = 5 * time.Second
+	stepTimeout        = 500 * time.Millisecond
+
+	detectorName = "aws.eks"
+
+	eksLabelPrefix   = "eks.amazonaws.com/"
+	fargatePrefix    = "fargate-"
+	clusterTagPrefix = "aws.eks.cluster.tag."
+	ec2TagPrefix     = "aws.ec2.tag."
+)
+
+// The steps of the detection. They can be given a share of the time budget
+// with [WithTimeBudget] and are traced with [WithTracer].
+const (
+	// StepDial is connecting to the Kubernetes API server.
+	StepDial = "dial"
+	// StepSTS is calling `sts:GetCallerIdentity`.
+	StepSTS = "sts"
+	// StepIMDS is each call to the EC2 instance metadata service.
+	StepIMDS = "imds"
+	// StepList is listing the EKS clusters.
+	StepList = "list"
+	// StepDescribe is each call to `eks:DescribeCluster`.
+	StepDescribe = "describe"
+)
+
+const (
+	localZoneKey      = attribute.Key("aws.local_zone")
+	partitionKey      = attribute.Key("aws.partition")
+	clusterVersionKey = attribute.Key("aws.eks.cluster.version")
+	candidatesKey     = attribute.Key("aws.eks.cluster.candidates")
+	lifecycleKey      = attribute.Key("aws.ec2.instance_lifecycle")
+	computeTypeKey    = attribute.Key("aws.eks.compute_type")
+	branchKey         = attribute.Key("aws.eks.detector.branch")
+)
+
+// The branches taken by the detection, recorded on the span started by
+// [WithTracer].
+const (
+	branchNotKubernetes = "not_kubernetes"
+	branchNotEKS        = "not_eks"
+	branchSkippedRegion = "skipped_region"
+	branchEC2           = "ec2"
+	branchEKS           = "eks"
+)
+
 type tlsConn interface {
 	Close() error
 	ConnectionState() tls.ConnectionState
 }
 
 type dialer interface {
-	dial(ctx context.Context, network, addr string, tlsConfig *tls.Config) (tlsConn, error)
+	dial(ctx context.Context, network, addr string, tlsConfig *tls.Config, timeout time.Duration) (tlsConn, error)
 }
 
 type eksListClustersPaginatorAPI interface {
@@ -35,7 +116,9 @@ type eksListClustersPaginatorAPI interface {
 	NextPage(ctx context.Context, fn ...func(*eks.Options)) (*eks.ListClustersOutput, error)
 }
 
-type eksAPIClient interface {
+// APIClient is the subset of the EKS API used to find the cluster. It is
+// satisfied by [*eks.Client].
+type APIClient interface {
 	eks.ListClustersAPIClient
 	eks.DescribeClusterAPIClient
 }
@@ -45,15 +128,30 @@ type stsAPIClient interface {
 	GetCallerIdentity(ctx context.Context, input *sts.GetCallerIdentityInput, fn ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
 }
 
+type imdsAPIClient interface {
+	//nolint:lll
+	GetInstanceIdentityDocument(ctx context.Context, input *imds.GetInstanceIdentityDocumentInput, fn ...func(*imds.Options)) (*imds.GetInstanceIdentityDocumentOutput, error)
+	GetMetadata(ctx context.Context, input *imds.GetMetadataInput, fn ...func(*imds.Options)) (*imds.GetMetadataOutput, error)
+	//nolint:lll
+	GetDynamicData(ctx context.Context, input *imds.GetDynamicDataInput, fn ...func(*imds.Options)) (*imds.GetDynamicDataOutput, error)
+}
+
 type detectorUtils interface {
 	dialer
+	lookupEnv(key string) (string, bool)
 	inClusterConfig() (*rest.Config, error)
-	stsClient(config aws.Config) stsAPIClient
-	eksClient(config aws.Config) eksAPIClient
+	nodeLabels(ctx context.Context, config *rest.Config, name string) (map[string]string, error)
+	stsClient(config aws.Config, optFns ...func(*sts.Options)) stsAPIClient
+	eksClient(config aws.Config, optFns ...func(*eks.Options)) APIClient
+	imdsClient(config aws.Config) imdsAPIClient
 }
 
 type eksDetectorUtils struct{}
 
+func (utils *eksDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
 func (utils *eksDetectorUtils) inClusterConfig() (*rest.Config, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -63,8 +161,53 @@ func (utils *eksDetectorUtils) inClusterConfig() (*rest.Config, error) {
 	return config, nil
 }
 
-func (utils *eksDetectorUtils) dial(ctx context.Context, network, addr string, config *tls.Config) (tlsConn, error) {
+//nolint:lll
+func (utils *eksDetectorUtils) nodeLabels(ctx context.Context, config *rest.Config, name string) (map[string]string, error) {
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	u, err := url.JoinPath(config.Host, "api", "v1", "nodes", name)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kubernetes URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting node: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %q", errUnexpectedStatus, resp.Status)
+	}
+
+	var node struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		return nil, fmt.Errorf("error decoding node: %w", err)
+	}
+
+	return node.Metadata.Labels, nil
+}
+
+//nolint:lll
+func (utils *eksDetectorUtils) dial(ctx context.Context, network, addr string, config *tls.Config, timeout time.Duration) (tlsConn, error) {
+	// The timeout covers both connecting and the TLS handshake
 	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{
+			Timeout: timeout,
+		},
 		Config: config,
 	}
 
@@ -77,59 +220,232 @@ func (utils *eksDetectorUtils) dial(ctx context.Context, network, addr string, c
 	return conn.(*tls.Conn), nil
 }
 
-func (utils *eksDetectorUtils) stsClient(cfg aws.Config) stsAPIClient {
-	return sts.NewFromConfig(cfg)
+func (utils *eksDetectorUtils) stsClient(cfg aws.Config, optFns ...func(*sts.Options)) stsAPIClient {
+	return sts.NewFromConfig(cfg, optFns...)
+}
+
+func (utils *eksDetectorUtils) eksClient(cfg aws.Config, optFns ...func(*eks.Options)) APIClient {
+	return eks.NewFromConfig(cfg, optFns...)
 }
 
-func (utils *eksDetectorUtils) eksClient(cfg aws.Config) eksAPIClient {
-	return eks.NewFromConfig(cfg)
+func (utils *eksDetectorUtils) imdsClient(cfg aws.Config) imdsAPIClient {
+	return imds.NewFromConfig(cfg)
 }
 
 type resourceDetector struct {
-	utils detectorUtils
+	utils           detectorUtils
+	network         string
+	dialTimeout     time.Duration
+	tlsMinVersion   uint16
+	endpointMatcher func(string, string) bool
+	clusterNameEnv  string
+	ec2Fallback     bool
+	regionFromIMDS  bool
+	transformer     func(*resource.Resource) (*resource.Resource, error)
+	maxClusters     int
+	prioritizer     func([]string, string) []string
+	skipRegions     []string
+	clusterFields   ClusterField
+	partialOnError  bool
+	retryer         func() aws.Retryer
+	awsConfig       *aws.Config
+	warn            func(error)
+	cache           Cache
+	customPrefix    string
+	logMode         aws.ClientLogMode
+	arnValidator    func(arn.ARN) error
+	fallbackAccount string
+	fingerprintKey  attribute.Key
+	platform        attribute.KeyValue
+	nameKey         attribute.Key
+	callerContext   bool
+	clusterNameTag  string
+	listCandidates  bool
+	eksOptions      []func(*eks.Options)
+	stsOptions      []func(*sts.Options)
+	detectLifecycle bool
+	verifier        func(document, signature []byte) error
+	maxAttributes   int
+	timeBudget      map[string]float64
+	tracer          trace.Tracer
+	computeType     bool
+	sources         []ClusterNameSource
+	redactedKeys    []attribute.Key
+	redactHasher    func([]byte) string
+	instanceTags    []string
+	regionAliases   map[string]string
+	negativeTTL     time.Duration
+
+	mu            sync.Mutex
+	state         *tls.ConnectionState
+	negativeUntil time.Time
+}
+
+//nolint:nonamedreturns
+func (detector *resourceDetector) Detect(ctx context.Context) (r *resource.Resource, err error) {
+	if detector.tracer != nil {
+		var span trace.Span
+
+		ctx, span = detector.tracer.Start(ctx, detectorName+".Detect")
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			span.End()
+		}()
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && len(detector.timeBudget) > 0 {
+		ctx = context.WithValue(ctx, budgetKey{}, time.Until(deadline))
+	}
+
+	// A partial resource may be returned alongside an error
+	r, err = detector.probe(ctx)
+	if r == nil {
+		return nil, err
+	}
+
+	// Truncate first so the fingerprint only covers the attributes returned
+	if limit := detector.attributeLimit(); detector.maxAttributes > 0 && r.Len() > limit {
+		r = newResource(detector.truncate(r.Attributes(), limit)...)
+	}
+
+	if detector.fingerprintKey != "" && r.Len() > 0 {
+		r = newResource(append(r.Attributes(), fingerprint(r, detector.fingerprintKey))...)
+	}
+
+	if detector.nameKey != "" && r.Len() > 0 {
+		r = newResource(append(r.Attributes(), detector.nameKey.String(detectorName))...)
+	}
+
+	if len(detector.redactedKeys) > 0 {
+		r = newResource(detector.redact(r.Attributes())...)
+	}
+
+	transformed, tErr := detector.transformer(r)
+	if tErr != nil {
+		tErr = fmt.Errorf("error transforming resource: %w", tErr)
+
+		// Keep the partial resource, as with any other error
+		if err != nil {
+			return r, errors.Join(err, tErr)
+		}
+
+		return nil, tErr
+	}
+
+	return transformed, err
+}
+
+// probe runs the detection unless an empty resource was detected within the
+// TTL set with [WithNegativeCacheTTL], in which case it is returned again.
+func (detector *resourceDetector) probe(ctx context.Context) (*resource.Resource, error) {
+	if detector.negativeTTL <= 0 {
+		return detector.detect(ctx)
+	}
+
+	detector.mu.Lock()
+	until := detector.negativeUntil
+	detector.mu.Unlock()
+
+	if time.Now().Before(until) {
+		return resource.Empty(), nil
+	}
+
+	r, err := detector.detect(ctx)
+	if err == nil && r != nil && r.Len() == 0 {
+		detector.mu.Lock()
+		detector.negativeUntil = time.Now().Add(detector.negativeTTL)
+		detector.mu.Unlock()
+	}
+
+	return r, err
 }
 
-func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+func (detector *resourceDetector) detect(ctx context.Context) (*resource.Resource, error) {
 	k8sConfig, err := detector.utils.inClusterConfig()
 	if err != nil {
 		// Not in a K8S cluster of any sort
 		if errors.Is(err, rest.ErrNotInCluster) {
+			setBranch(ctx, branchNotKubernetes)
+
 			return resource.Empty(), nil
 		}
 
 		return nil, err
 	}
 
-	names, err := getK8SCertificateDNSNames(ctx, k8sConfig, detector.utils)
+	state, err := detector.getK8SConnectionState(ctx, k8sConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint, region, ok := detectEKS(names)
+	detector.mu.Lock()
+	detector.state = &state
+	detector.mu.Unlock()
+
+	endpoint, region, ok := detector.identifyEKS(ctx, k8sConfig, state)
 	if !ok {
 		// It's a K8S cluster, but not EKS
+		if detector.ec2Fallback {
+			setBranch(ctx, branchEC2)
+
+			return detector.detectEC2(ctx)
+		}
+
+		setBranch(ctx, branchNotEKS)
+
 		return resource.Empty(), nil
 	}
 
-	attributes := []attribute.KeyValue{
-		semconv.CloudProviderAWS,
-		semconv.CloudPlatformAWSEKS,
-		semconv.CloudRegion(region),
+	region, localZone := splitLocalZone(region)
+
+	if slices.Contains(detector.skipRegions, region) {
+		setBranch(ctx, branchSkippedRegion)
+
+		return resource.Empty(), nil
 	}
 
-	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRetryer(func() aws.Retryer {
-		return new(aws.NopRetryer)
-	}))
+	setBranch(ctx, branchEKS)
+
+	return detector.detectCluster(ctx, endpoint, region, localZone)
+}
+
+// identifyEKS returns the endpoint and region of the EKS cluster from the API
+// server certificate, falling back to the instance metadata if
+// [WithRegionFromIMDS] is used.
+//
+//nolint:lll
+func (detector *resourceDetector) identifyEKS(ctx context.Context, config *rest.Config, state tls.ConnectionState) (string, string, bool) {
+	endpoint, region, ok := detectEKS(certificateDNSNames(state))
+	if !ok && detector.regionFromIMDS {
+		region, ok = detector.detectEKSFromIMDS(ctx, config)
+	}
+
+	return endpoint, region, ok
+}
+
+// detectCluster detects the attributes of the EKS cluster in the region.
+//
+//nolint:lll
+func (detector *resourceDetector) detectCluster(ctx context.Context, endpoint, region, localZone string) (*resource.Resource, error) {
+	attributes := detector.regionAttributes(region, localZone)
+
+	awsConfig, err := detector.loadAWSConfig(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load AWS config: %w", err)
+		return nil, err
 	}
 
-	stsClient := detector.utils.stsClient(awsConfig)
+	attributes = append(attributes, detector.nodeAttributes(ctx, awsConfig)...)
+
+	detector.checkRegion(awsConfig, region)
 
-	accountID, err := getAccountID(ctx, stsClient)
+	accountID, err := detector.accountID(ctx, awsConfig)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+			return newResource(attributes...), nil
 		}
 
 		return nil, err
@@ -137,170 +453,1837 @@ func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resourc
 
 	attributes = append(attributes, semconv.CloudAccountID(accountID))
 
-	eksClient := detector.utils.eksClient(awsConfig)
-
-	clusterName, err := findEKSClusterByEndpoint(ctx, eksClient, endpoint)
+	clusterAttributes, err := detector.clusterAttributes(ctx, awsConfig, endpoint)
 	if err != nil {
-		return nil, err
+		return detector.partial(append(attributes, clusterAttributes...), err)
 	}
 
-	if clusterName != "" {
-		attributes = append(attributes, semconv.K8SClusterName(clusterName))
+	return newResource(append(attributes, clusterAttributes...)...), nil
+}
+
+// regionAttributes returns the attributes derived from the region.
+func (detector *resourceDetector) regionAttributes(region, localZone string) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		detector.platform,
+		semconv.CloudRegion(detector.regionAlias(region)),
+		detector.customKey(partitionKey).String(partitionForRegion(region)),
+	}
+
+	if localZone != "" {
+		attributes = append(attributes, detector.customKey(localZoneKey).String(localZone))
 	}
 
-	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+	return attributes
 }
 
-var _ resource.Detector = new(resourceDetector)
+// nodeAttributes returns the attributes of the node the pod is running on,
+// if [WithComputeType], [WithInstanceLifecycle], or [WithInstanceTags] are
+// used.
+func (detector *resourceDetector) nodeAttributes(ctx context.Context, awsConfig aws.Config) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+
+	fargate := false
+
+	if detector.computeType {
+		if nodeName, _ := detector.utils.lookupEnv(NodeNameEnv); nodeName != "" {
+			fargate = strings.HasPrefix(nodeName, fargatePrefix)
+
+			attributes = append(attributes, detector.customKey(computeTypeKey).String(computeType(fargate)))
+		}
+	}
+
+	// There's no EC2 instance to describe with Fargate
+	if !fargate && (detector.detectLifecycle || len(detector.instanceTags) > 0) {
+		imdsClient := detector.utils.imdsClient(awsConfig)
+
+		if detector.detectLifecycle {
+			attributes = append(attributes, detector.lifecycle(ctx, imdsClient)...)
+		}
 
-// NewResourceDetector returns a [resource.Detector] that will detect AWS EKS resources.
-func NewResourceDetector() resource.Detector {
-	return &resourceDetector{
-		utils: new(eksDetectorUtils),
+		attributes = append(attributes, detector.tags(ctx, imdsClient)...)
 	}
+
+	return attributes
 }
 
-//nolint:nonamedreturns
-func getK8SCertificateDNSNames(ctx context.Context, config *rest.Config, dialer dialer) (names []string, err error) {
+// checkRegion warns if the region of the config set with [WithAWSConfig]
+// disagrees with the detected region. The certificate, or instance metadata,
+// is more authoritative so the detected region is still used. The default
+// config is not checked as AWS_REGION may deliberately differ.
+func (detector *resourceDetector) checkRegion(awsConfig aws.Config, region string) {
+	if detector.awsConfig != nil && awsConfig.Region != "" && awsConfig.Region != region {
+		detector.warn(fmt.Errorf("%w: detected %q, AWS config has %q", errRegionMismatch, region, awsConfig.Region))
+	}
+}
+
+// accountID returns the account ID of the caller, or the account ID set with
+// [WithFallbackAccountID] if the `sts:GetCallerIdentity` call fails.
+func (detector *resourceDetector) accountID(ctx context.Context, awsConfig aws.Config) (string, error) {
+	stsClient := detector.utils.stsClient(awsConfig, detector.stsOptions...)
+
+	ctx, cancel := detector.stepContext(ctx, StepSTS)
+	defer cancel()
+
+	// Only a failed call falls back, an invalid response is still an error
+	accountID, err := getAccountID(ctx, stsClient, detector.arnValidator)
+	if errors.Is(err, errGetCallerIdentity) && detector.fallbackAccount != "" {
+		return detector.fallbackAccount, nil
+	}
+
+	return accountID, err
+}
+
+// clusterAttributes returns the cluster name and any other attributes of the
+// cluster with the endpoint. If an error occurs then any attributes detected
+// so far are returned alongside it.
+//
+//nolint:lll
+func (detector *resourceDetector) clusterAttributes(ctx context.Context, awsConfig aws.Config, endpoint string) ([]attribute.KeyValue, error) {
 	var (
-		tlsConfig *tls.Config
-		conn      tlsConn
+		attributes []attribute.KeyValue
+		eksClient  APIClient
+		info       *clusterInfo
+		err        error
 	)
 
-	tlsConfig, err = rest.TLSConfigFor(config)
-	if err != nil {
-		return
+	clusterName := detector.knownClusterName(ctx, endpoint)
+	if clusterName == "" {
+		eksClient = detector.utils.eksClient(awsConfig, detector.eksOptions...)
+
+		clusterName, info, attributes, err = detector.searchClusterName(ctx, eksClient, endpoint)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	conn, err = dialer.dial(ctx, "tcp", strings.TrimPrefix(config.Host, "https://"), tlsConfig)
-	if err != nil {
-		return
+	if clusterName == "" {
+		return attributes, nil
 	}
 
-	defer func() {
-		err = conn.Close()
-	}()
+	if detector.describeFields() != 0 && info == nil {
+		if eksClient == nil {
+			eksClient = detector.utils.eksClient(awsConfig, detector.eksOptions...)
+		}
 
-	for _, cert := range conn.ConnectionState().PeerCertificates {
-		names = append(names, cert.DNSNames...)
+		info, err = detector.describeCluster(ctx, eksClient, clusterName)
+		if err != nil {
+			return append(attributes, semconv.K8SClusterName(clusterName)), err
+		}
 	}
 
-	return
+	attributes = append(attributes, semconv.K8SClusterName(detector.clusterNameFromTag(clusterName, info)))
+
+	if detector.clusterFields != 0 {
+		attributes = append(attributes, info.attributes(detector.customPrefix)...)
+	}
+
+	return attributes, nil
+}
+
+// knownClusterName returns the cluster name from the environment variable set
+// with [WithClusterNameEnv] or the cache set with [WithClusterNameCache], if
+// either has it. Neither needs an EKS client.
+func (detector *resourceDetector) knownClusterName(ctx context.Context, endpoint string) string {
+	var sources []ClusterNameSource
+
+	if detector.clusterNameEnv != "" {
+		sources = append(sources, ClusterNameFromEnv(detector.clusterNameEnv))
+	}
+
+	if detector.cache != nil {
+		sources = append(sources, cacheSource{detector.cache})
+	}
+
+	// Neither source returns an error
+	clusterName, _, _, _ := detector.clusterNameFromSources(ctx, nil, endpoint, sources)
+
+	return clusterName
 }
 
+// searchClusterName resolves the cluster name and caches it. The candidate
+// cluster names are returned as an attribute if
+// [WithAmbiguousClusterAttribute] is used.
+//
 //nolint:lll
-var eksEndpointRegexp = regexp.MustCompile(`\.(?P<region>[^.]+)\.(?:eks\.amazonaws\.com|api\.aws|(?:api\.)?amazonwebservices\.com\.cn)$`)
+func (detector *resourceDetector) searchClusterName(ctx context.Context, client APIClient, endpoint string) (string, *clusterInfo, []attribute.KeyValue, error) {
+	clusterName, info, candidates, err := detector.resolveClusterName(ctx, client, endpoint)
+	if err != nil {
+		return "", nil, nil, err
+	}
 
-func detectEKS(names []string) (string, string, bool) {
-	for _, name := range names {
-		if match := eksEndpointRegexp.FindStringSubmatch(name); match != nil {
-			m := make(map[string]string)
+	var attributes []attribute.KeyValue
 
-			for i, name := range eksEndpointRegexp.SubexpNames() {
-				if i > 0 {
-					m[name] = match[i]
-				}
-			}
+	if detector.listCandidates && len(candidates) > 0 {
+		attributes = append(attributes, detector.customKey(candidatesKey).String(strings.Join(candidates, ",")))
+	}
 
-			return name, m["region"], true
-		}
+	if clusterName != "" && detector.cache != nil {
+		detector.cache.Set(endpoint, clusterName)
 	}
 
-	return "", "", false
+	return clusterName, info, attributes, nil
 }
 
-func getAccountID(ctx context.Context, client stsAPIClient) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
-	defer cancel()
+// partial returns the attributes detected so far as a resource alongside the
+// error if [WithPartialOnError] is used, otherwise just the error.
+func (detector *resourceDetector) partial(attributes []attribute.KeyValue, err error) (*resource.Resource, error) {
+	if detector.partialOnError {
+		return newResource(attributes...), err
+	}
 
-	output, err := client.GetCallerIdentity(ctx, new(sts.GetCallerIdentityInput))
-	if err != nil {
-		return "", fmt.Errorf("error issuing `sts:GetCallerIdentity`: %w", err)
+	return nil, err
+}
+
+// newResource returns a resource with the attributes. The SDK already stores
+// them as an [attribute.Set], which is sorted by key with the last value of any
+// repeated key winning, so the result is the same regardless of the order
+// they were detected in.
+func newResource(attributes ...attribute.KeyValue) *resource.Resource {
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...)
+}
+
+// regionAlias returns the alias for the region set with [WithRegionAlias], or
+// the region itself if there isn't one.
+func (detector *resourceDetector) regionAlias(region string) string {
+	if alias, ok := detector.regionAliases[region]; ok {
+		return alias
 	}
 
-	arn, err := arn.Parse(*output.Arn)
-	if err != nil {
-		return "", fmt.Errorf("error parsing ARN: %w", err)
+	return region
+}
+
+// priorityKeys are the keys in order of importance when the number of
+// attributes is limited. Any other keys follow, with cluster and EC2 instance
+// tags last.
+//
+//nolint:gochecknoglobals
+var priorityKeys = []attribute.Key{
+	semconv.CloudProviderKey,
+	semconv.CloudPlatformKey,
+	semconv.CloudRegionKey,
+	semconv.CloudAccountIDKey,
+	semconv.K8SClusterNameKey,
+	semconv.AWSEKSClusterARNKey,
+	semconv.CloudAvailabilityZoneKey,
+	semconv.HostIDKey,
+	semconv.HostTypeKey,
+	semconv.HostImageIDKey,
+}
+
+// attributeLimit returns how many of the detected attributes are kept with
+// [WithMaxAttributes], leaving room for the fingerprint and name attributes
+// which are always kept.
+func (detector *resourceDetector) attributeLimit() int {
+	limit := detector.maxAttributes
+
+	if detector.fingerprintKey != "" {
+		limit--
+	}
+
+	if detector.nameKey != "" {
+		limit--
 	}
 
-	return arn.AccountID, nil
+	return max(limit, 0)
 }
 
-func listEKSClustersPaginated(ctx context.Context, paginator eksListClustersPaginatorAPI) ([]string, error) {
-	var clusters []string
+// truncate returns the most important attributes, up to the limit.
+func (detector *resourceDetector) truncate(attributes []attribute.KeyValue, limit int) []attribute.KeyValue {
+	tagPrefixes := []string{
+		detector.customPrefix + clusterTagPrefix,
+		detector.customPrefix + ec2TagPrefix,
+	}
 
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("error issuing `eks:ListClusters`: %w", err)
+	priority := func(key attribute.Key) int {
+		if i := slices.Index(priorityKeys, key); i >= 0 {
+			return i
 		}
 
-		clusters = append(clusters, output.Clusters...)
+		if slices.ContainsFunc(tagPrefixes, func(prefix string) bool {
+			return strings.HasPrefix(string(key), prefix)
+		}) {
+			return len(priorityKeys) + 1
+		}
+
+		return len(priorityKeys)
 	}
 
-	return clusters, nil
+	attributes = slices.Clone(attributes)
+
+	// The attributes of a resource are sorted by key by the SDK, and this sort
+	// is stable, so keys with the same priority remain in that order
+	slices.SortStableFunc(attributes, func(a, b attribute.KeyValue) int {
+		return priority(a.Key) - priority(b.Key)
+	})
+
+	return attributes[:limit]
 }
 
-func listEKSClusters(ctx context.Context, client eks.ListClustersAPIClient) ([]string, error) {
-	paginator := eks.NewListClustersPaginator(client,
-		new(eks.ListClustersInput),
-		func(o *eks.ListClustersPaginatorOptions) {
-			o.Limit = 20
-		})
+// customKey returns the key with any prefix set by
+// [WithCustomAttributePrefix]. It must only be used for keys that aren't part
+// of the semantic conventions.
+func (detector *resourceDetector) customKey(key attribute.Key) attribute.Key {
+	return attribute.Key(detector.customPrefix) + key
+}
 
-	output, err := listEKSClustersPaginated(ctx, paginator)
-	if err != nil {
-		return nil, err
+func (detector *resourceDetector) clusterNameFromEnv() string {
+	if detector.clusterNameEnv == "" {
+		return ""
 	}
 
-	return output, nil
+	clusterName, _ := detector.utils.lookupEnv(detector.clusterNameEnv)
+
+	return clusterName
 }
 
-func describeEKSClusterEndpoint(ctx context.Context, client eks.DescribeClusterAPIClient, name string) (string, error) {
-	input := &eks.DescribeClusterInput{
-		Name: aws.String(name),
+func (detector *resourceDetector) detectEKSFromIMDS(ctx context.Context, config *rest.Config) (string, bool) {
+	nodeName, _ := detector.utils.lookupEnv(NodeNameEnv)
+	if nodeName == "" {
+		return "", false
 	}
 
-	output, err := client.DescribeCluster(ctx, input)
+	labels, err := detector.utils.nodeLabels(ctx, config, nodeName)
 	if err != nil {
-		return "", fmt.Errorf("error issuing `eks:DescribeCluster`: %w", err)
+		return "", false
 	}
 
-	return *output.Cluster.Endpoint, nil
-}
+	if !hasEKSLabel(labels) {
+		return "", false
+	}
+
+	awsConfig, err := detector.loadAWSConfig(ctx)
+	if err != nil {
+		return "", false
+	}
 
-func findEKSClusterByEndpoint(ctx context.Context, client eksAPIClient, endpoint string) (string, error) {
-	const accessDeniedException = "AccessDeniedException"
+	stepCtx, cancel := detector.stepContext(ctx, StepIMDS)
+	defer cancel()
 
-	clusters, err := listEKSClusters(ctx, client)
+	document, err := getInstanceIdentityDocument(stepCtx, detector.utils.imdsClient(awsConfig))
 	if err != nil {
-		var ae smithy.APIError
-		if errors.As(err, &ae) && ae.ErrorCode() == accessDeniedException {
-			return "", nil
+		// Fall back to the region from the AWS config, if there is one
+		return awsConfig.Region, awsConfig.Region != ""
+	}
+
+	return document.Region, true
+}
+
+func (detector *resourceDetector) detectEC2(ctx context.Context) (*resource.Resource, error) {
+	awsConfig, err := detector.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imdsClient := detector.utils.imdsClient(awsConfig)
+
+	stepCtx, cancel := detector.stepContext(ctx, StepIMDS)
+	defer cancel()
+
+	var document *imds.InstanceIdentityDocument
+
+	if detector.verifier != nil {
+		document, err = getVerifiedInstanceIdentityDocument(stepCtx, imdsClient, detector.verifier)
+		if errors.Is(err, errUnverified) {
+			// Don't trust any of the instance metadata
+			detector.warn(err)
+
+			return resource.Empty(), nil
 		}
+	} else {
+		document, err = getInstanceIdentityDocument(stepCtx, imdsClient)
+	}
 
-		return "", err
+	if err != nil {
+		// Not on EC2 either
+		return resource.Empty(), nil //nolint:nilerr
 	}
 
-	if len(clusters) == 1 {
-		return clusters[0], nil
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEC2,
+		semconv.CloudRegion(detector.regionAlias(document.Region)),
+		detector.customKey(partitionKey).String(partitionForRegion(document.Region)),
+		semconv.CloudAvailabilityZone(document.AvailabilityZone),
+		semconv.CloudAccountID(document.AccountID),
+		semconv.HostID(document.InstanceID),
+		semconv.HostType(document.InstanceType),
+		semconv.HostImageID(document.ImageID),
 	}
 
-	for _, cluster := range clusters {
-		ep, err := describeEKSClusterEndpoint(ctx, client, cluster)
-		if err != nil {
-			var ae smithy.APIError
-			if errors.As(err, &ae) && ae.ErrorCode() == accessDeniedException {
+	if detector.detectLifecycle {
+		attributes = append(attributes, detector.lifecycle(ctx, imdsClient)...)
+	}
+
+	attributes = append(attributes, detector.tags(ctx, imdsClient)...)
+
+	if clusterName := detector.clusterNameFromEnv(); clusterName != "" {
+		attributes = append(attributes, semconv.K8SClusterName(clusterName))
+	}
+
+	return newResource(attributes...), nil
+}
+
+// computeType returns the value of the compute type attribute.
+func computeType(fargate bool) string {
+	if fargate {
+		return "fargate"
+	}
+
+	return "ec2"
+}
+
+// lifecycle returns the instance lifecycle attribute, if the instance
+// metadata responds.
+func (detector *resourceDetector) lifecycle(ctx context.Context, client imdsAPIClient) []attribute.KeyValue {
+	stepCtx, cancel := detector.stepContext(ctx, StepIMDS)
+	defer cancel()
+
+	lifecycle, err := getInstanceLifecycle(stepCtx, client)
+	if err != nil || lifecycle == "" {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		detector.customKey(lifecycleKey).String(lifecycle),
+	}
+}
+
+// tags returns an attribute for each of the instance tags set with
+// [WithInstanceTags] that is present in the instance metadata. If access to
+// the tags isn't enabled in the instance metadata then no attributes are
+// returned.
+func (detector *resourceDetector) tags(ctx context.Context, client imdsAPIClient) []attribute.KeyValue {
+	if len(detector.instanceTags) == 0 {
+		return nil
+	}
+
+	stepCtx, cancel := detector.stepContext(ctx, StepIMDS)
+	defer cancel()
+
+	// This returns a 404 if access to the tags isn't enabled
+	keys, err := getMetadata(stepCtx, client, "tags/instance")
+	if err != nil {
+		return nil
+	}
+
+	available := strings.Fields(keys)
+
+	var attributes []attribute.KeyValue
+
+	for _, key := range detector.instanceTags {
+		if !slices.Contains(available, key) {
+			continue
+		}
+
+		value, err := detector.instanceTag(ctx, client, key)
+		if err != nil {
+			continue
+		}
+
+		attributes = append(attributes, detector.customKey(attribute.Key(ec2TagPrefix+key)).String(value))
+	}
+
+	return attributes
+}
+
+func (detector *resourceDetector) instanceTag(ctx context.Context, client imdsAPIClient, key string) (string, error) {
+	ctx, cancel := detector.stepContext(ctx, StepIMDS)
+	defer cancel()
+
+	return getMetadata(ctx, client, "tags/instance/"+key)
+}
+
+// Close releases the TLS connection state cached from the most recent
+// detection, see [ConnectionState], and forgets any empty resource cached
+// with [WithNegativeCacheTTL]. The AWS and Kubernetes clients are created for
+// each detection so nothing else is held. It is safe to call more than once.
+func (detector *resourceDetector) Close() error {
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+
+	detector.state = nil
+	detector.negativeUntil = time.Time{}
+
+	return nil
+}
+
+var (
+	_ resource.Detector = new(resourceDetector)
+	_ io.Closer         = new(resourceDetector)
+)
+
+// ConnectionState returns the TLS connection state negotiated with the
+// Kubernetes API server during the most recent detection by a
+// [resource.Detector] returned by [NewResourceDetector]. It is intended for
+// debugging TLS interoperability issues, such as which version and cipher
+// suite were used. It returns false if the detector is not an EKS detector or
+// it has not yet connected to the API server.
+func ConnectionState(detector resource.Detector) (tls.ConnectionState, bool) {
+	d, ok := detector.(*resourceDetector)
+	if !ok {
+		return tls.ConnectionState{}, false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state == nil {
+		return tls.ConnectionState{}, false
+	}
+
+	return *d.state, true
+}
+
+// A Cache stores EKS cluster names keyed by the API server endpoint. It must
+// be safe for concurrent use.
+type Cache interface {
+	// Get returns the cluster name for the endpoint, if there is one.
+	Get(endpoint string) (string, bool)
+	// Set stores the cluster name for the endpoint.
+	Set(endpoint, clusterName string)
+}
+
+type memoryCache struct {
+	mu sync.RWMutex
+	m  map[string]string
+}
+
+func (c *memoryCache) Get(endpoint string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clusterName, ok := c.m[endpoint]
+
+	return clusterName, ok
+}
+
+func (c *memoryCache) Set(endpoint, clusterName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.m[endpoint] = clusterName
+}
+
+// NewCache returns an in-memory [Cache] with no expiry, suitable for
+// [WithClusterNameCache].
+func NewCache() Cache {
+	return &memoryCache{
+		m: make(map[string]string),
+	}
+}
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithNetwork sets the network used when dialing the Kubernetes API server to
+// inspect its certificate, for example "tcp4" or "tcp6" to force a particular
+// address family on a dual-stack cluster. The default is "tcp".
+func WithNetwork(network string) Option {
+	return func(detector *resourceDetector) {
+		detector.network = network
+	}
+}
+
+// WithDialNetTimeout sets the timeout for connecting to the Kubernetes API
+// server, including the TLS handshake, so that detection doesn't hang if the
+// API server is firewalled. The default is 5 seconds.
+func WithDialNetTimeout(timeout time.Duration) Option {
+	return func(detector *resourceDetector) {
+		detector.dialTimeout = timeout
+	}
+}
+
+// WithTLSMinVersion sets the minimum TLS version, such as [tls.VersionTLS12],
+// used when connecting to the Kubernetes API server to inspect its
+// certificate. By default the minimum version is left as the Kubernetes client
+// would set it.
+func WithTLSMinVersion(version uint16) Option {
+	return func(detector *resourceDetector) {
+		detector.tlsMinVersion = version
+	}
+}
+
+// WithEndpointMatcher sets the function used to compare the API server
+// endpoint, as found in its certificate, against the endpoint returned by
+// `eks:DescribeCluster`. The default strips any "https://" prefix from the
+// latter and compares case-insensitively.
+func WithEndpointMatcher(fn func(certEndpoint, describeEndpoint string) bool) Option {
+	return func(detector *resourceDetector) {
+		detector.endpointMatcher = fn
+	}
+}
+
+// WithClusterNameEnv sets the name of an environment variable that, if
+// populated, provides the cluster name. This avoids needing any EKS API calls,
+// and therefore any IAM permissions, to detect the cluster name.
+func WithClusterNameEnv(key string) Option {
+	return func(detector *resourceDetector) {
+		detector.clusterNameEnv = key
+	}
+}
+
+// WithEC2Fallback enables detection of self-managed Kubernetes clusters, such
+// as those built with kops or kubeadm, running on EC2. If the cluster is not
+// EKS but the EC2 instance metadata service responds, EC2 platform and host
+// attributes are detected instead. The cluster name is only detected if
+// [WithClusterNameEnv] is also used.
+func WithEC2Fallback() Option {
+	return func(detector *resourceDetector) {
+		detector.ec2Fallback = true
+	}
+}
+
+// WithInstanceIdentityVerification enables verification of the EC2 instance
+// identity document used by [WithEC2Fallback] against the AWS public
+// certificates for the region, which are published in the EC2 documentation.
+// The SHA-256 RSA signature of the document is checked, and if it isn't
+// signed by any of the certificates then no attributes are detected. This
+// prevents spoofed instance metadata producing false attributes.
+func WithInstanceIdentityVerification(certs ...*x509.Certificate) Option {
+	return func(detector *resourceDetector) {
+		detector.verifier = certificateVerifier(certs)
+	}
+}
+
+// WithMaxClusters limits the number of EKS clusters that will be listed and
+// described when searching for the cluster name. If the account has more
+// clusters than this, the search is abandoned and no cluster name is detected.
+// The default of zero means no limit.
+func WithMaxClusters(n int) Option {
+	return func(detector *resourceDetector) {
+		detector.maxClusters = n
+	}
+}
+
+// A ClusterNameSource is a source of the EKS cluster name for
+// [WithClusterNameSources].
+type ClusterNameSource interface {
+	// ClusterName returns the name of the EKS cluster from the endpoint found
+	// in the certificate of the Kubernetes API server, such as
+	// "abc123.gr7.eu-west-1.eks.amazonaws.com". An empty name means the
+	// source doesn't know the cluster name.
+	ClusterName(ctx context.Context, client APIClient, endpoint string) (string, error)
+}
+
+// The ClusterNameFunc type is an adapter to allow the use of ordinary
+// functions as a [ClusterNameSource].
+type ClusterNameFunc func(ctx context.Context, client APIClient, endpoint string) (string, error)
+
+// ClusterName calls fn(ctx, client, endpoint).
+func (fn ClusterNameFunc) ClusterName(ctx context.Context, client APIClient, endpoint string) (string, error) {
+	return fn(ctx, client, endpoint)
+}
+
+// A detectorSource is a [ClusterNameSource] that, when used by the detector,
+// honours its other options and can also return the described cluster and
+// any candidate cluster names.
+type detectorSource interface {
+	ClusterNameSource
+	//nolint:lll
+	clusterName(ctx context.Context, detector *resourceDetector, client APIClient, endpoint string) (string, *clusterInfo, []string, error)
+}
+
+type apiSource struct{}
+
+func (s apiSource) ClusterName(ctx context.Context, client APIClient, endpoint string) (string, error) {
+	name, _, _, err := s.clusterName(ctx, newResourceDetector(new(eksDetectorUtils)), client, endpoint)
+
+	return name, err
+}
+
+//nolint:lll
+func (apiSource) clusterName(ctx context.Context, detector *resourceDetector, client APIClient, endpoint string) (string, *clusterInfo, []string, error) {
+	return detector.findEKSClusterByEndpoint(ctx, client, endpoint)
+}
+
+// ClusterNameFromAPI returns a [ClusterNameSource] that lists the EKS clusters
+// and describes each in turn to find the one with a matching endpoint. This is
+// how the cluster name is found by default.
+func ClusterNameFromAPI() ClusterNameSource {
+	return apiSource{}
+}
+
+type envSource string
+
+func (key envSource) ClusterName(_ context.Context, _ APIClient, _ string) (string, error) {
+	name, _ := os.LookupEnv(string(key))
+
+	return name, nil
+}
+
+//nolint:lll
+func (key envSource) clusterName(_ context.Context, detector *resourceDetector, _ APIClient, _ string) (string, *clusterInfo, []string, error) {
+	name, _ := detector.utils.lookupEnv(string(key))
+
+	return name, nil, nil, nil
+}
+
+// ClusterNameFromEnv returns a [ClusterNameSource] that reads the cluster name
+// from the named environment variable.
+func ClusterNameFromEnv(key string) ClusterNameSource {
+	return envSource(key)
+}
+
+type staticSource string
+
+func (name staticSource) ClusterName(_ context.Context, _ APIClient, _ string) (string, error) {
+	return string(name), nil
+}
+
+// StaticClusterName returns a [ClusterNameSource] that always returns the
+// given cluster name. It is most useful as the last source.
+func StaticClusterName(name string) ClusterNameSource {
+	return staticSource(name)
+}
+
+type cacheSource struct {
+	cache Cache
+}
+
+func (s cacheSource) ClusterName(_ context.Context, _ APIClient, endpoint string) (string, error) {
+	name, _ := s.cache.Get(endpoint)
+
+	return name, nil
+}
+
+// WithClusterNameSources sets the sources of the EKS cluster name, such as
+// [ClusterNameFromAPI], [ClusterNameFromEnv], [StaticClusterName], or any
+// other [ClusterNameSource]. They are tried in order and the first to return a
+// non-empty name is used, so a later source can provide the name if an
+// earlier one fails. An error is only returned if none of them do. The sources
+// set with [WithClusterNameEnv] and [WithClusterNameCache] are always tried
+// first, without creating an EKS client. This replaces any function set with
+// [WithClusterNameResolver], and vice versa, so only the last of the two
+// options has any effect. The default is to only use [ClusterNameFromAPI].
+func WithClusterNameSources(sources ...ClusterNameSource) Option {
+	return func(detector *resourceDetector) {
+		detector.sources = sources
+	}
+}
+
+// WithClusterNameResolver sets a function that resolves the name of the EKS
+// cluster from the endpoint found in the certificate of the Kubernetes API
+// server, such as "abc123.gr7.eu-west-1.eks.amazonaws.com". This replaces
+// listing and describing the clusters, for example to use a naming convention
+// or to look up the name in an external inventory. An empty name means the
+// cluster name is not detected. It is the same as passing the function as the
+// only source to [WithClusterNameSources], which it replaces.
+func WithClusterNameResolver(fn func(ctx context.Context, client APIClient, endpoint string) (string, error)) Option {
+	return WithClusterNameSources(ClusterNameFunc(fn))
+}
+
+// WithClusterCandidatePrioritizer sets a function that reorders the listed EKS
+// clusters before each is described in turn to find the one matching the
+// endpoint. Putting the most likely candidates first, for example based on a
+// naming convention, reduces the number of `eks:DescribeCluster` calls. The
+// default is to use the order returned by `eks:ListClusters`.
+func WithClusterCandidatePrioritizer(fn func(clusters []string, endpoint string) []string) Option {
+	return func(detector *resourceDetector) {
+		detector.prioritizer = fn
+	}
+}
+
+// WithComputeType enables detection of whether the pod is running on Fargate
+// or on an EC2 node, using the node name from the [NodeNameEnv] environment
+// variable. Fargate node names start with "fargate-". An
+// "aws.eks.compute_type" attribute is added with a value of either "fargate"
+// or "ec2" and, on Fargate, the EC2 instance attributes enabled with
+// [WithInstanceLifecycle] or [WithInstanceTags] are omitted. The attribute is
+// only added if the environment variable is set. The default is to not detect
+// the compute type.
+func WithComputeType() Option {
+	return func(detector *resourceDetector) {
+		detector.computeType = true
+	}
+}
+
+// WithRegionFromIMDS enables detection of EKS clusters where the API server
+// certificate has been customized and lacks the usual EKS endpoint. If the
+// node, named by the [NodeNameEnv] environment variable, has any
+// "eks.amazonaws.com/" labels and the EC2 instance metadata service responds
+// then the region is taken from the instance metadata and detection carries
+// on as normal. The pod's service account needs permission to get nodes.
+func WithRegionFromIMDS() Option {
+	return func(detector *resourceDetector) {
+		detector.regionFromIMDS = true
+	}
+}
+
+// WithResultTransformer sets a function that is passed the detected resource,
+// including any empty resource, just before it is returned. It can return a
+// modified resource, for example with keys renamed or extra attributes added,
+// or an error that is returned from Detect. If the error follows a partial
+// resource from [WithPartialOnError] then the untransformed partial resource is
+// returned with both errors. The default returns the resource unchanged.
+func WithResultTransformer(fn func(*resource.Resource) (*resource.Resource, error)) Option {
+	return func(detector *resourceDetector) {
+		detector.transformer = fn
+	}
+}
+
+// WithSkipRegions sets a list of regions where detection is skipped. If the
+// detected region is in the list then an empty resource is returned before
+// any AWS API calls are made.
+func WithSkipRegions(regions ...string) Option {
+	return func(detector *resourceDetector) {
+		detector.skipRegions = regions
+	}
+}
+
+// WithRegionAlias sets a map of regions to aliases, such as "eu-west-1" to
+// "dublin", for organizations that use their own names for regions. If the
+// detected region is in the map then its alias is used for the cloud.region
+// attribute, otherwise the region is used unchanged. The region itself is
+// still used for the AWS API calls, the partition and [WithSkipRegions].
+func WithRegionAlias(aliases map[string]string) Option {
+	return func(detector *resourceDetector) {
+		detector.regionAliases = maps.Clone(aliases)
+	}
+}
+
+// WithDescribeClusterFields enables detection of additional cluster fields
+// from the `eks:DescribeCluster` response. The API always returns the full
+// cluster object however only the endpoint, needed to identify the cluster,
+// and the enabled fields are retained. By default no additional fields are
+// detected and the detector needs only the `eks:ListClusters` and
+// `eks:DescribeCluster` permissions, and no `eks:DescribeCluster` call is made
+// if there's only one cluster or the cluster name is found using
+// [WithClusterNameEnv]. Enabling any field means the cluster is always
+// described once its name is known.
+func WithDescribeClusterFields(fields ...ClusterField) Option {
+	return func(detector *resourceDetector) {
+		for _, field := range fields {
+			detector.clusterFields |= field
+		}
+	}
+}
+
+// WithClusterNameFromTag sets the key of an EKS cluster tag whose value is
+// used as the cluster name rather than the name of the EKS cluster, which is
+// used if the tag isn't present. This requires permission to use
+// `eks:DescribeCluster`. The tags are only included as attributes if
+// [ClusterTags] is also passed to [WithDescribeClusterFields].
+func WithClusterNameFromTag(key string) Option {
+	return func(detector *resourceDetector) {
+		detector.clusterNameTag = key
+	}
+}
+
+// WithAmbiguousClusterAttribute adds an attribute listing the candidate
+// cluster names, comma-separated, if the cluster can't be identified because
+// access to `eks:DescribeCluster` is denied for some of them. This helps
+// operators to investigate rather than the cluster name silently being
+// omitted. The default is no candidates attribute.
+func WithAmbiguousClusterAttribute() Option {
+	return func(detector *resourceDetector) {
+		detector.listCandidates = true
+	}
+}
+
+// WithInstanceTags adds an attribute for each of the given instance tags, such
+// as "aws.ec2.tag.team", using the instance metadata service. Access to the
+// tags has to be enabled in the instance metadata options. Only the given
+// tags are read to limit the cardinality of the attributes and a tag is
+// skipped if it isn't present. The default is to not detect any tags.
+func WithInstanceTags(keys ...string) Option {
+	return func(detector *resourceDetector) {
+		detector.instanceTags = append(detector.instanceTags, keys...)
+	}
+}
+
+// WithInstanceLifecycle enables detection of the purchasing option of the
+// node, such as "spot" or "on-demand", using the instance metadata service.
+// The attribute is only added if the instance metadata responds. The default
+// is to not detect the instance lifecycle.
+func WithInstanceLifecycle() Option {
+	return func(detector *resourceDetector) {
+		detector.detectLifecycle = true
+	}
+}
+
+// WithMaxAttributes limits the number of attributes detected to n, which
+// protects backends that limit the number of attributes from options such as
+// [WithDescribeClusterFields] with [ClusterTags]. The most important
+// attributes are kept, in order: cloud.provider, cloud.platform,
+// cloud.region, cloud.account.id, k8s.cluster.name, aws.eks.cluster.arn,
+// cloud.availability_zone, host.id, host.type, and host.image.id, followed
+// by any other attributes and lastly the cluster tags and the EC2 instance
+// tags from [WithInstanceTags], each sorted by key.
+// The attributes added by [WithFingerprintAttribute] and
+// [WithDetectorNameAttribute] are always kept and count towards the limit,
+// and the fingerprint only covers the other attributes that are kept. The
+// limit is applied before any function set with [WithResultTransformer] is
+// called. The default is no limit.
+func WithMaxAttributes(n int) Option {
+	return func(detector *resourceDetector) {
+		detector.maxAttributes = n
+	}
+}
+
+// WithTimeBudget divides the deadline of the context passed to Detect
+// between the steps of the detection, such as [StepDial] or [StepSTS], with
+// each step allowed the given fraction of the total time. This avoids one slow
+// step consuming the whole budget. The fixed timeouts are still used for any
+// step not in the map, or if the context has no deadline. The default is to
+// only use the fixed timeouts.
+func WithTimeBudget(budget map[string]float64) Option {
+	return func(detector *resourceDetector) {
+		detector.timeBudget = maps.Clone(budget)
+	}
+}
+
+// WithTracer traces the detection using the given tracer. A span covers the
+// whole of Detect, with an attribute recording the branch taken, and each
+// step of the detection, such as [StepDial] or [StepSTS], has a child span.
+// The default is no tracing.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(detector *resourceDetector) {
+		detector.tracer = tracer
+	}
+}
+
+// WithPartialOnError changes the behaviour when an error occurs while looking
+// up the cluster name, such as an `eks:DescribeCluster` call failing for a
+// reason other than access being denied. Rather than returning only the error,
+// the resource detected so far, including the account ID, is returned
+// alongside it so the caller can decide whether to use it. The default is to
+// return a nil resource with the error.
+func WithPartialOnError() Option {
+	return func(detector *resourceDetector) {
+		detector.partialOnError = true
+	}
+}
+
+// WithRetryer sets the function used to create the retryer for all AWS API
+// calls. The default never retries. Unless [WithRespectCallerContextOnly] is
+// used, the `sts:GetCallerIdentity` call is bounded by the detector's own
+// timeout of 500 milliseconds, so any backoff delay between attempts counts
+// against it, and if it runs out of time then a partial resource without the
+// account ID is returned. The `eks:ListClusters` and `eks:DescribeCluster`
+// calls have no timeout of their own, so retrying them can extend detection
+// by the full backoff unless the context passed to Detect has a deadline, or
+// [WithTimeBudget] gives them a share of it. See [DefaultThrottleRetryer] for
+// a suitable retryer.
+func WithRetryer(fn func() aws.Retryer) Option {
+	return func(detector *resourceDetector) {
+		detector.retryer = fn
+	}
+}
+
+// WithNegativeCacheTTL caches an empty resource for the given duration so
+// that detecting again within that time returns an empty resource without
+// probing the Kubernetes API server or the EC2 instance metadata service
+// again. This avoids repeatedly waiting for unreachable endpoints when
+// detection is retried rapidly. Errors are never cached. The default is no
+// caching.
+func WithNegativeCacheTTL(ttl time.Duration) Option {
+	return func(detector *resourceDetector) {
+		detector.negativeTTL = ttl
+	}
+}
+
+// WithClusterNameCache sets a cache of cluster names, keyed by the API server
+// endpoint, that is checked before searching for the cluster name using the
+// EKS API. Any cluster name found by searching is added to the cache. Sharing
+// the same cache between detectors, or detecting repeatedly with the same
+// detector, then avoids repeating the search. Use [NewCache] for a simple
+// in-memory cache. The default is no cache.
+func WithClusterNameCache(cache Cache) Option {
+	return func(detector *resourceDetector) {
+		detector.cache = cache
+	}
+}
+
+// WithCustomAttributePrefix sets a prefix added to the keys of the attributes
+// that aren't part of the semantic conventions, such as "aws.partition" and
+// "aws.local_zone", to namespace them and avoid collisions. The prefix is used
+// as-is so should normally end with a ".". Standard semantic convention keys
+// are never prefixed. The default is no prefix.
+func WithCustomAttributePrefix(prefix string) Option {
+	return func(detector *resourceDetector) {
+		detector.customPrefix = prefix
+	}
+}
+
+// WithAWSConfig sets the AWS config used for all AWS API calls rather than
+// loading the default config. [WithRetryer] has no effect on it, although the
+// log mode set with [WithAWSClientLogMode] is applied to a copy of it. The
+// region is normally taken from the API server certificate, which always
+// includes one, so the region of the config is only used with
+// [WithRegionFromIMDS] when the certificate doesn't identify an EKS cluster
+// and the instance metadata service doesn't respond either. If the region of
+// the config disagrees with the detected region then the detected region is
+// still used and a warning is reported, see [WithWarningHandler]. The region
+// of the default config is never checked.
+func WithAWSConfig(cfg aws.Config) Option {
+	return func(detector *resourceDetector) {
+		detector.awsConfig = &cfg
+	}
+}
+
+// WithWarningHandler sets a function that is passed any non-fatal problems
+// found during detection, such as the region of the config set with
+// [WithAWSConfig] disagreeing with the detected region, or the instance
+// identity document failing [WithInstanceIdentityVerification]. Detection
+// carries on regardless. The default passes them to the global OpenTelemetry
+// error handler, see [otel.Handle].
+func WithWarningHandler(fn func(error)) Option {
+	return func(detector *resourceDetector) {
+		detector.warn = fn
+	}
+}
+
+// WithAWSClientLogMode sets the log mode of the AWS SDK clients used for the
+// STS and EKS API calls, for example
+// `aws.LogRequest | aws.LogResponse | aws.LogRetries`, which is useful for
+// debugging why detection fails in a particular account. Logs are written to
+// the logger of the AWS config. This also applies to any config set with
+// [WithAWSConfig]. The default is no logging.
+func WithAWSClientLogMode(mode aws.ClientLogMode) Option {
+	return func(detector *resourceDetector) {
+		detector.logMode = mode
+	}
+}
+
+// WithEKSOptions sets functions that are passed to [eks.NewFromConfig] when
+// creating the EKS client. This allows any option of the client to be
+// changed, such as the endpoint or user agent.
+func WithEKSOptions(optFns ...func(*eks.Options)) Option {
+	return func(detector *resourceDetector) {
+		detector.eksOptions = append(detector.eksOptions, optFns...)
+	}
+}
+
+// WithSTSOptions sets functions that are passed to [sts.NewFromConfig] when
+// creating the STS client. This allows any option of the client to be
+// changed, such as the endpoint or user agent.
+func WithSTSOptions(optFns ...func(*sts.Options)) Option {
+	return func(detector *resourceDetector) {
+		detector.stsOptions = append(detector.stsOptions, optFns...)
+	}
+}
+
+// WithARNValidator sets a function used to validate the caller ARN returned
+// by `sts:GetCallerIdentity` before the account ID is taken from it, for
+// example to check the partition or that the caller is an assumed role. If it
+// returns an error then detection fails with that error. The default is no
+// extra validation.
+func WithARNValidator(fn func(arn.ARN) error) Option {
+	return func(detector *resourceDetector) {
+		detector.arnValidator = fn
+	}
+}
+
+// WithFallbackAccountID sets the account ID to use if the
+// `sts:GetCallerIdentity` call fails for any reason, including timing out,
+// such as when STS is blocked but the account ID is already known. Detection
+// then carries on as normal. The account ID returned by STS is always
+// preferred, and if the call succeeds but the caller ARN can't be parsed or
+// is rejected by [WithARNValidator] then detection still fails. The default is
+// no fallback.
+func WithFallbackAccountID(accountID string) Option {
+	return func(detector *resourceDetector) {
+		detector.fallbackAccount = accountID
+	}
+}
+
+// WithFingerprintAttribute adds an attribute with the given key whose value is
+// the hex-encoded SHA-256 hash of the other detected attributes. The hash
+// doesn't depend on the order the attributes are detected in so it provides
+// a stable identity, for example to deduplicate resources across restarts.
+// It is calculated before any function set with [WithResultTransformer] is
+// called and isn't added to an empty resource. The default is no fingerprint.
+func WithFingerprintAttribute(key string) Option {
+	return func(detector *resourceDetector) {
+		detector.fingerprintKey = attribute.Key(key)
+	}
+}
+
+// WithRedactedAttributes replaces the values of the attributes with the given
+// keys, such as [semconv.CloudAccountIDKey] or [semconv.K8SClusterNameKey],
+// with their hash. The keys are kept so resources can still be correlated
+// without exposing the raw values. If hasher is nil then the first 16
+// characters of the hex-encoded SHA-256 hash are used. The values are hashed
+// after any fingerprint set with [WithFingerprintAttribute] is calculated. The
+// default is to not redact any attributes.
+func WithRedactedAttributes(hasher func([]byte) string, keys ...string) Option {
+	return func(detector *resourceDetector) {
+		if hasher == nil {
+			hasher = defaultHasher
+		}
+
+		detector.redactHasher = hasher
+
+		for _, key := range keys {
+			detector.redactedKeys = append(detector.redactedKeys, attribute.Key(key))
+		}
+	}
+}
+
+// WithDetectorNameAttribute adds an attribute with the given key whose value
+// is the name of this detector, "aws.eks". This helps to trace which detector
+// produced a resource when several are merged, although only one value
+// survives the merge. It isn't added to an empty resource. The default is no
+// name attribute.
+func WithDetectorNameAttribute(key string) Option {
+	return func(detector *resourceDetector) {
+		detector.nameKey = attribute.Key(key)
+	}
+}
+
+// WithRespectCallerContextOnly disables the timeouts the detector applies
+// internally, such as when dialing the Kubernetes API server or calling
+// `sts:GetCallerIdentity`, so only the deadline of the context passed to
+// Detect applies. This is useful when the caller already sets an appropriate
+// deadline. The default is to apply the internal timeouts.
+func WithRespectCallerContextOnly() Option {
+	return func(detector *resourceDetector) {
+		detector.callerContext = true
+	}
+}
+
+// WithCloudPlatformOverride sets the attribute used in place of the default
+// [semconv.CloudPlatformAWSEKS] attribute. This allows EKS variants that
+// can't be distinguished by the detector, such as EKS Anywhere or EKS on
+// Outposts, to be identified with a different cloud.platform value.
+func WithCloudPlatformOverride(platform attribute.KeyValue) Option {
+	return func(detector *resourceDetector) {
+		detector.platform = platform
+	}
+}
+
+// DefaultThrottleRetryer returns a function, suitable for [WithRetryer], that
+// creates the AWS SDK standard retryer limited to maxAttempts attempts in
+// total. It retries throttling and transient errors with an exponential
+// backoff and full jitter, which avoids many pods started at the same time
+// retrying in lockstep.
+func DefaultThrottleRetryer(maxAttempts int) func() aws.Retryer {
+	return func() aws.Retryer {
+		return retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = maxAttempts
+		})
+	}
+}
+
+func newResourceDetector(utils detectorUtils, options ...Option) *resourceDetector {
+	detector := &resourceDetector{
+		utils:           utils,
+		network:         "tcp",
+		dialTimeout:     defaultDialTimeout,
+		endpointMatcher: defaultEndpointMatcher,
+		transformer:     defaultTransformer,
+		retryer:         defaultRetryer,
+		warn:            otel.Handle,
+		platform:        semconv.CloudPlatformAWSEKS,
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS EKS
+// resources. As with any resource, the SDK keeps the attributes sorted by key
+// so the output is deterministic, regardless of which options are used or the
+// order they are passed in. The detector also implements [io.Closer] and
+// should be closed when the SDK is shut down.
+func NewResourceDetector(options ...Option) resource.Detector {
+	return newResourceDetector(new(eksDetectorUtils), options...)
+}
+
+func (detector *resourceDetector) loadAWSConfig(ctx context.Context) (aws.Config, error) {
+	var awsConfig aws.Config
+
+	if detector.awsConfig != nil {
+		awsConfig = detector.awsConfig.Copy()
+	} else {
+		var err error
+
+		awsConfig, err = config.LoadDefaultConfig(ctx, config.WithRetryer(detector.retryer))
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("unable to load AWS config: %w", err)
+		}
+	}
+
+	if detector.logMode != 0 {
+		awsConfig.ClientLogMode = detector.logMode
+	}
+
+	return awsConfig, nil
+}
+
+//nolint:lll,nonamedreturns
+func (detector *resourceDetector) getK8SConnectionState(ctx context.Context, config *rest.Config) (state tls.ConnectionState, err error) {
+	var (
+		tlsConfig *tls.Config
+		conn      tlsConn
+	)
+
+	tlsConfig, err = rest.TLSConfigFor(config)
+	if err != nil {
+		return
+	}
+
+	if detector.tlsMinVersion != 0 {
+		if tlsConfig == nil {
+			tlsConfig = new(tls.Config)
+		}
+
+		tlsConfig.MinVersion = detector.tlsMinVersion
+	}
+
+	timeout := detector.dialTimeout
+	if detector.callerContext || detector.budgeted(ctx, StepDial) {
+		timeout = 0
+	}
+
+	ctx, cancel := detector.stepContext(ctx, StepDial)
+	defer cancel()
+
+	conn, err = detector.utils.dial(ctx, detector.network, strings.TrimPrefix(config.Host, "https://"), tlsConfig, timeout)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		err = conn.Close()
+	}()
+
+	state = conn.ConnectionState()
+
+	return
+}
+
+// setBranch records the branch taken by the detection on the current span, if
+// any.
+func setBranch(ctx context.Context, branch string) {
+	trace.SpanFromContext(ctx).SetAttributes(branchKey.String(branch))
+}
+
+// budgetKey is the context key for the total time budget of a detection.
+type budgetKey struct{}
+
+// budgeted reports whether the step has a share of the time budget set with
+// [WithTimeBudget].
+func (detector *resourceDetector) budgeted(ctx context.Context, step string) bool {
+	_, ok := ctx.Value(budgetKey{}).(time.Duration)
+	_, hasStep := detector.timeBudget[step]
+
+	return ok && hasStep
+}
+
+// stepContext returns a context for a single step of the detection, traced
+// with a span if [WithTracer] is used.
+func (detector *resourceDetector) stepContext(ctx context.Context, step string) (context.Context, context.CancelFunc) {
+	ctx, cancel := detector.stepDeadline(ctx, step)
+	if detector.tracer == nil {
+		return ctx, cancel
+	}
+
+	ctx, span := detector.tracer.Start(ctx, detectorName+"."+step)
+
+	return ctx, func() {
+		span.End()
+		cancel()
+	}
+}
+
+// stepDeadline returns a context for a single step of the detection. If the
+// step has a share of the time budget then that is used, otherwise calls to
+// the AWS APIs or instance metadata, which are expected to respond quickly,
+// have a fixed timeout.
+func (detector *resourceDetector) stepDeadline(ctx context.Context, step string) (context.Context, context.CancelFunc) {
+	if detector.callerContext {
+		return ctx, func() {}
+	}
+
+	if detector.budgeted(ctx, step) {
+		budget, _ := ctx.Value(budgetKey{}).(time.Duration)
+
+		return context.WithTimeout(ctx, time.Duration(float64(budget)*detector.timeBudget[step]))
+	}
+
+	if step == StepSTS || step == StepIMDS {
+		return context.WithTimeout(ctx, stepTimeout)
+	}
+
+	return ctx, func() {}
+}
+
+func certificateDNSNames(state tls.ConnectionState) []string {
+	var names []string
+
+	for _, cert := range state.PeerCertificates {
+		names = append(names, cert.DNSNames...)
+	}
+
+	return names
+}
+
+//nolint:lll
+var eksEndpointRegexp = regexp.MustCompile(`\.(?P<region>[^.]+)\.(?:eks\.amazonaws\.com|api\.aws|(?:api\.)?amazonwebservices\.com\.cn)$`)
+
+func detectEKS(names []string) (string, string, bool) {
+	for _, name := range names {
+		if match := eksEndpointRegexp.FindStringSubmatch(name); match != nil {
+			m := make(map[string]string)
+
+			for i, name := range eksEndpointRegexp.SubexpNames() {
+				if i > 0 {
+					m[name] = match[i]
+				}
+			}
+
+			return name, m["region"], true
+		}
+	}
+
+	return "", "", false
+}
+
+// Local Zone and Outposts identifiers extend the parent region, such as
+// "us-east-1-bos-1a".
+var localZoneRegexp = regexp.MustCompile(`^([a-z]{2}(?:-[a-z]+)+-\d+)-[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+func splitLocalZone(region string) (string, string) {
+	if match := localZoneRegexp.FindStringSubmatch(region); match != nil {
+		return match[1], region
+	}
+
+	return region, ""
+}
+
+//nolint:gochecknoglobals
+var partitions = []struct {
+	prefix    string
+	partition string
+}{
+	{"cn-", "aws-cn"},
+	{"us-gov-", "aws-us-gov"},
+	{"us-iso-", "aws-iso"},
+	{"us-isob-", "aws-iso-b"},
+	{"eu-isoe-", "aws-iso-e"},
+	{"us-isof-", "aws-iso-f"},
+}
+
+func partitionForRegion(region string) string {
+	for _, p := range partitions {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+
+	return "aws"
+}
+
+func hasEKSLabel(labels map[string]string) bool {
+	for label := range labels {
+		if strings.HasPrefix(label, eksLabelPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getAccountID(ctx context.Context, client stsAPIClient, validator func(arn.ARN) error) (string, error) {
+	output, err := client.GetCallerIdentity(ctx, new(sts.GetCallerIdentityInput))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errGetCallerIdentity, err)
+	}
+
+	account := aws.ToString(output.Account)
+
+	if output.Arn == nil {
+		if account != "" {
+			return account, nil
+		}
+
+		return "", errMissingARN
+	}
+
+	a, err := arn.Parse(*output.Arn)
+	if err != nil {
+		return "", fmt.Errorf("error parsing ARN: %w", err)
+	}
+
+	if validator != nil {
+		if err := validator(a); err != nil {
+			return "", fmt.Errorf("error validating ARN: %w", err)
+		}
+	}
+
+	if a.AccountID != "" {
+		return a.AccountID, nil
+	}
+
+	if account != "" {
+		return account, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", errMissingAccountID, *output.Arn)
+}
+
+//nolint:lll
+func getInstanceIdentityDocument(ctx context.Context, client imdsAPIClient) (*imds.InstanceIdentityDocument, error) {
+	output, err := client.GetInstanceIdentityDocument(ctx, new(imds.GetInstanceIdentityDocumentInput))
+	if err != nil {
+		return nil, fmt.Errorf("error getting instance identity document: %w", err)
+	}
+
+	return &output.InstanceIdentityDocument, nil
+}
+
+// getVerifiedInstanceIdentityDocument returns the instance identity document
+// only if its signature is verified.
+//
+//nolint:lll
+func getVerifiedInstanceIdentityDocument(ctx context.Context, client imdsAPIClient, verifier func([]byte, []byte) error) (*imds.InstanceIdentityDocument, error) {
+	b, err := getDynamicData(ctx, client, "instance-identity/document")
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := getDynamicData(ctx, client, "instance-identity/signature")
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decoding signature: %w", errUnverified, err)
+	}
+
+	if err := verifier(b, decoded); err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnverified, err)
+	}
+
+	document := new(imds.InstanceIdentityDocument)
+	if err := json.Unmarshal(b, document); err != nil {
+		return nil, fmt.Errorf("error decoding instance identity document: %w", err)
+	}
+
+	return document, nil
+}
+
+func getDynamicData(ctx context.Context, client imdsAPIClient, path string) ([]byte, error) {
+	output, err := client.GetDynamicData(ctx, &imds.GetDynamicDataInput{
+		Path: path,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s: %w", path, err)
+	}
+	defer output.Content.Close()
+
+	b, err := io.ReadAll(output.Content)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+// certificateVerifier returns a function that verifies the SHA-256 RSA
+// signature of the instance identity document against any of the
+// certificates.
+func certificateVerifier(certs []*x509.Certificate) func([]byte, []byte) error {
+	return func(document, signature []byte) error {
+		errs := make([]error, 0, len(certs))
+
+		for _, cert := range certs {
+			err := cert.CheckSignature(x509.SHA256WithRSA, document, signature)
+			if err == nil {
+				return nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		if len(errs) == 0 {
+			return errNoCertificates
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// getInstanceLifecycle returns the purchasing option of the instance, such as
+// "spot" or "on-demand".
+func getInstanceLifecycle(ctx context.Context, client imdsAPIClient) (string, error) {
+	return getMetadata(ctx, client, "instance-life-cycle")
+}
+
+func getMetadata(ctx context.Context, client imdsAPIClient, path string) (string, error) {
+	output, err := client.GetMetadata(ctx, &imds.GetMetadataInput{
+		Path: path,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error getting %s: %w", path, err)
+	}
+	defer output.Content.Close()
+
+	b, err := io.ReadAll(output.Content)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+var (
+	errTooManyClusters   = errors.New("too many clusters")
+	errUnexpectedStatus  = errors.New("unexpected status")
+	errRegionMismatch    = errors.New("region mismatch")
+	errGetCallerIdentity = errors.New("error issuing `sts:GetCallerIdentity`")
+	errMissingARN        = errors.New("missing ARN")
+	errMissingAccountID  = errors.New("missing account ID")
+	errUnverified        = errors.New("instance identity document not verified")
+	errNoCertificates    = errors.New("no certificates")
+)
+
+//nolint:lll
+func listEKSClustersPaginated(ctx context.Context, paginator eksListClustersPaginatorAPI, maxClusters int) ([]string, error) {
+	var clusters []string
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error issuing `eks:ListClusters`: %w", err)
+		}
+
+		clusters = append(clusters, output.Clusters...)
+
+		if maxClusters > 0 && len(clusters) > maxClusters {
+			return nil, errTooManyClusters
+		}
+	}
+
+	return clusters, nil
+}
+
+func listEKSClusters(ctx context.Context, client eks.ListClustersAPIClient, maxClusters int) ([]string, error) {
+	paginator := eks.NewListClustersPaginator(client,
+		new(eks.ListClustersInput),
+		func(o *eks.ListClustersPaginatorOptions) {
+			o.Limit = 20
+		})
+
+	output, err := listEKSClustersPaginated(ctx, paginator, maxClusters)
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// A ClusterField is an optional field of the `eks:DescribeCluster` response.
+type ClusterField uint
+
+// The optional cluster fields that can be detected.
+const (
+	// ClusterARN detects the cluster ARN as the "aws.eks.cluster.arn"
+	// attribute.
+	ClusterARN ClusterField = 1 << iota
+	// ClusterVersion detects the Kubernetes version of the cluster as the
+	// "aws.eks.cluster.version" attribute.
+	ClusterVersion
+	// ClusterTags detects each cluster tag as an
+	// "aws.eks.cluster.tag.<key>" attribute.
+	ClusterTags
+)
+
+// clusterInfo holds only the fields of the `eks:DescribeCluster` response
+// that are needed, the rest is discarded as soon as possible.
+type clusterInfo struct {
+	endpoint string
+	arn      string
+	version  string
+	tags     map[string]string
+}
+
+func (info *clusterInfo) attributes(prefix string) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+
+	if info.arn != "" {
+		attributes = append(attributes, semconv.AWSEKSClusterARN(info.arn))
+	}
+
+	if info.version != "" {
+		attributes = append(attributes, attribute.String(prefix+string(clusterVersionKey), info.version))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(info.tags)) {
+		attributes = append(attributes, attribute.String(prefix+clusterTagPrefix+key, info.tags[key]))
+	}
+
+	return attributes
+}
+
+//nolint:lll
+func describeEKSCluster(ctx context.Context, client eks.DescribeClusterAPIClient, name string, fields ClusterField) (*clusterInfo, error) {
+	input := &eks.DescribeClusterInput{
+		Name: aws.String(name),
+	}
+
+	output, err := client.DescribeCluster(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing `eks:DescribeCluster`: %w", err)
+	}
+
+	info := &clusterInfo{
+		endpoint: aws.ToString(output.Cluster.Endpoint),
+	}
+
+	if fields&ClusterARN != 0 {
+		info.arn = aws.ToString(output.Cluster.Arn)
+	}
+
+	if fields&ClusterVersion != 0 {
+		info.version = aws.ToString(output.Cluster.Version)
+	}
+
+	if fields&ClusterTags != 0 && len(output.Cluster.Tags) > 0 {
+		info.tags = maps.Clone(output.Cluster.Tags)
+	}
+
+	return info, nil
+}
+
+// fingerprint returns the SHA-256 hash of the resource attributes, in their
+// canonical sorted encoding, under the given key.
+func fingerprint(r *resource.Resource, key attribute.Key) attribute.KeyValue {
+	sum := sha256.Sum256([]byte(r.Encoded(attribute.DefaultEncoder())))
+
+	return key.String(hex.EncodeToString(sum[:]))
+}
+
+// redact replaces the values of the attributes set with
+// [WithRedactedAttributes] with their hash.
+func (detector *resourceDetector) redact(attributes []attribute.KeyValue) []attribute.KeyValue {
+	for i, kv := range attributes {
+		if slices.Contains(detector.redactedKeys, kv.Key) {
+			attributes[i] = kv.Key.String(detector.redactHasher([]byte(kv.Value.Emit())))
+		}
+	}
+
+	return attributes
+}
+
+// defaultHasher returns the first 16 characters of the hex-encoded SHA-256
+// hash of b.
+func defaultHasher(b []byte) string {
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:8])
+}
+
+func defaultRetryer() aws.Retryer {
+	return new(aws.NopRetryer)
+}
+
+func defaultTransformer(r *resource.Resource) (*resource.Resource, error) {
+	return r, nil
+}
+
+func defaultEndpointMatcher(certEndpoint, describeEndpoint string) bool {
+	return endpointsMatch(certEndpoint, describeEndpoint)
+}
+
+// endpointsMatch reports whether two endpoints refer to the same host. Either
+// may be a bare hostname or a URL with a scheme and trailing slash, and host
+// names are compared without regard to case.
+func endpointsMatch(a, b string) bool {
+	return normalizeEndpoint(a) == normalizeEndpoint(b)
+}
+
+func normalizeEndpoint(endpoint string) string {
+	endpoint = strings.ToLower(endpoint)
+
+	for _, scheme := range []string{"https://", "http://"} {
+		if v, ok := strings.CutPrefix(endpoint, scheme); ok {
+			endpoint = v
+
+			break
+		}
+	}
+
+	return strings.TrimRight(endpoint, "/")
+}
+
+const accessDeniedException = "AccessDeniedException"
+
+// describeFields returns the cluster fields to describe, which includes the
+// tags if the cluster name is taken from one.
+func (detector *resourceDetector) describeFields() ClusterField {
+	if detector.clusterNameTag != "" {
+		return detector.clusterFields | ClusterTags
+	}
+
+	return detector.clusterFields
+}
+
+// clusterNameFromTag returns the value of the cluster name tag, if there is
+// one, otherwise the EKS cluster name. Any tags that were only described to
+// find the name are discarded.
+func (detector *resourceDetector) clusterNameFromTag(name string, info *clusterInfo) string {
+	if info == nil || detector.clusterNameTag == "" {
+		return name
+	}
+
+	v := info.tags[detector.clusterNameTag]
+
+	if detector.clusterFields&ClusterTags == 0 {
+		info.tags = nil
+	}
+
+	if v != "" {
+		return v
+	}
+
+	return name
+}
+
+// resolveClusterName resolves the name of the EKS cluster using the sources set
+// with [WithClusterNameSources] or [WithClusterNameResolver] if there are any,
+// otherwise by finding the cluster by its endpoint.
+//
+//nolint:lll
+func (detector *resourceDetector) resolveClusterName(ctx context.Context, client APIClient, endpoint string) (string, *clusterInfo, []string, error) {
+	sources := detector.sources
+	if len(sources) == 0 {
+		sources = []ClusterNameSource{ClusterNameFromAPI()}
+	}
+
+	return detector.clusterNameFromSources(ctx, client, endpoint, sources)
+}
+
+// clusterNameFromSources tries each of the sources in turn until one returns a
+// cluster name. An error is only returned if no source returns a cluster name.
+//
+//nolint:lll
+func (detector *resourceDetector) clusterNameFromSources(ctx context.Context, client APIClient, endpoint string, sources []ClusterNameSource) (string, *clusterInfo, []string, error) {
+	var (
+		errs       []error
+		candidates []string
+	)
+
+	for _, source := range sources {
+		name, info, c, err := detector.clusterNameFromSource(ctx, source, client, endpoint)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		candidates = append(candidates, c...)
+
+		if name != "" {
+			return name, info, candidates, nil
+		}
+	}
+
+	return "", nil, candidates, errors.Join(errs...)
+}
+
+// clusterNameFromSource returns the cluster name from the source. The built-in
+// sources also return the described cluster and any candidate cluster names.
+//
+//nolint:lll
+func (detector *resourceDetector) clusterNameFromSource(ctx context.Context, source ClusterNameSource, client APIClient, endpoint string) (string, *clusterInfo, []string, error) {
+	if s, ok := source.(detectorSource); ok {
+		return s.clusterName(ctx, detector, client, endpoint)
+	}
+
+	name, err := source.ClusterName(ctx, client, endpoint)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("error resolving cluster name: %w", err)
+	}
+
+	return name, nil, nil, nil
+}
+
+// describeEKSCluster describes the named cluster within its own step context.
+//
+//nolint:lll
+func (detector *resourceDetector) describeEKSCluster(ctx context.Context, client eks.DescribeClusterAPIClient, name string) (*clusterInfo, error) {
+	ctx, cancel := detector.stepContext(ctx, StepDescribe)
+	defer cancel()
+
+	return describeEKSCluster(ctx, client, name, detector.describeFields())
+}
+
+// describeCluster describes the named cluster retaining only the enabled
+// fields. If access is denied then an empty result is returned.
+//
+//nolint:lll
+func (detector *resourceDetector) describeCluster(ctx context.Context, client APIClient, name string) (*clusterInfo, error) {
+	info, err := detector.describeEKSCluster(ctx, client, name)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == accessDeniedException {
+			return new(clusterInfo), nil
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// findEKSClusterByEndpoint returns the name of the cluster whose endpoint
+// matches. If there's no match then any clusters that couldn't be described
+// are returned as candidates.
+//
+//nolint:lll
+func (detector *resourceDetector) findEKSClusterByEndpoint(ctx context.Context, client APIClient, endpoint string) (string, *clusterInfo, []string, error) {
+	listCtx, cancel := detector.stepContext(ctx, StepList)
+	defer cancel()
+
+	clusters, err := listEKSClusters(listCtx, client, detector.maxClusters)
+	if err != nil {
+		if errors.Is(err, errTooManyClusters) {
+			return "", nil, nil, nil
+		}
+
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == accessDeniedException {
+			return "", nil, nil, nil
+		}
+
+		return "", nil, nil, err
+	}
+
+	if len(clusters) == 1 {
+		return clusters[0], nil, nil, nil
+	}
+
+	if detector.prioritizer != nil {
+		clusters = detector.prioritizer(clusters, endpoint)
+	}
+
+	var candidates []string
+
+	for _, cluster := range clusters {
+		info, err := detector.describeEKSCluster(ctx, client, cluster)
+		if err != nil {
+			var ae smithy.APIError
+			if errors.As(err, &ae) && ae.ErrorCode() == accessDeniedException {
+				candidates = append(candidates, cluster)
+
 				continue
 			}
 
-			return "", err
+			return "", nil, nil, err
 		}
 
-		if strings.TrimPrefix(strings.ToLower(ep), "https://") == endpoint {
-			return cluster, nil
+		if detector.endpointMatcher(endpoint, info.endpoint) {
+			return cluster, info, nil, nil
 		}
 	}
 
-	return "", nil
+	return "", nil, candidates, nil
 }
@@ -2,26 +2,51 @@
 package eks
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/bodgit/detectors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
 	"k8s.io/client-go/rest"
 )
 
 const testHost = "192.0.2.1:443"
 
+var errTest = errors.New("test")
+
 type mockTLSConn struct {
 	mock.Mock
 }
@@ -36,6 +61,14 @@ func (conn *mockTLSConn) ConnectionState() tls.ConnectionState {
 
 type mockDetectorUtils struct {
 	mock.Mock
+	stsOptions []func(*sts.Options)
+	eksOptions []func(*eks.Options)
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
 }
 
 func (utils *mockDetectorUtils) inClusterConfig() (*rest.Config, error) {
@@ -48,18 +81,40 @@ func (utils *mockDetectorUtils) inClusterConfig() (*rest.Config, error) {
 	return nil, args.Error(1)
 }
 
-func (utils *mockDetectorUtils) dial(ctx context.Context, network, addr string, tlsConfig *tls.Config) (tlsConn, error) {
-	args := utils.Called(ctx, network, addr, tlsConfig)
+func (utils *mockDetectorUtils) nodeLabels(ctx context.Context, config *rest.Config, name string) (map[string]string, error) {
+	args := utils.Called(ctx, config, name)
+
+	if labels := args.Get(0); labels != nil {
+		return labels.(map[string]string), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (utils *mockDetectorUtils) dial(ctx context.Context, network, addr string, tlsConfig *tls.Config, timeout time.Duration) (tlsConn, error) {
+	args := utils.Called(ctx, network, addr, tlsConfig, timeout)
+
+	if conn := args.Get(0); conn != nil {
+		return conn.(tlsConn), args.Error(1)
+	}
 
-	return args.Get(0).(tlsConn), args.Error(1)
+	return nil, args.Error(1)
 }
 
-func (utils *mockDetectorUtils) stsClient(config aws.Config) stsAPIClient {
+func (utils *mockDetectorUtils) stsClient(config aws.Config, optFns ...func(*sts.Options)) stsAPIClient {
+	utils.stsOptions = optFns
+
 	return utils.Called(config).Get(0).(stsAPIClient)
 }
 
-func (utils *mockDetectorUtils) eksClient(config aws.Config) eksAPIClient {
-	return utils.Called(config).Get(0).(eksAPIClient)
+func (utils *mockDetectorUtils) eksClient(config aws.Config, optFns ...func(*eks.Options)) APIClient {
+	utils.eksOptions = optFns
+
+	return utils.Called(config).Get(0).(APIClient)
+}
+
+func (utils *mockDetectorUtils) imdsClient(config aws.Config) imdsAPIClient {
+	return utils.Called(config).Get(0).(imdsAPIClient)
 }
 
 type mockSTSClient struct {
@@ -100,13 +155,47 @@ func (client *mockEKSClient) DescribeCluster(ctx context.Context, input *eks.Des
 	return nil, args.Error(1)
 }
 
+type mockIMDSClient struct {
+	mock.Mock
+}
+
+func (client *mockIMDSClient) GetInstanceIdentityDocument(ctx context.Context, input *imds.GetInstanceIdentityDocumentInput, optFns ...func(*imds.Options)) (*imds.GetInstanceIdentityDocumentOutput, error) {
+	args := client.Called(ctx, input, optFns)
+
+	if output := args.Get(0); output != nil {
+		return output.(*imds.GetInstanceIdentityDocumentOutput), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (client *mockIMDSClient) GetMetadata(ctx context.Context, input *imds.GetMetadataInput, optFns ...func(*imds.Options)) (*imds.GetMetadataOutput, error) {
+	args := client.Called(ctx, input, optFns)
+
+	if output := args.Get(0); output != nil {
+		return output.(*imds.GetMetadataOutput), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (client *mockIMDSClient) GetDynamicData(ctx context.Context, input *imds.GetDynamicDataInput, optFns ...func(*imds.Options)) (*imds.GetDynamicDataOutput, error) {
+	args := client.Called(ctx, input, optFns)
+
+	if output := args.Get(0); output != nil {
+		return output.(*imds.GetDynamicDataOutput), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func TestNotInCluster(t *testing.T) {
 	t.Parallel()
 
 	utils := new(mockDetectorUtils)
 	utils.On("inClusterConfig").Return(nil, rest.ErrNotInCluster).Once()
 
-	eksResourceDetector := resourceDetector{utils: utils}
+	eksResourceDetector := newResourceDetector(utils)
 
 	r, err := eksResourceDetector.Detect(t.Context())
 	require.NoError(t, err)
@@ -136,9 +225,9 @@ func TestNotEKS(t *testing.T) {
 		},
 	}).Once()
 
-	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything).Return(conn, nil).Once()
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
 
-	eksResourceDetector := resourceDetector{utils: utils}
+	eksResourceDetector := newResourceDetector(utils)
 
 	r, err := eksResourceDetector.Detect(t.Context())
 	require.NoError(t, err)
@@ -170,7 +259,7 @@ func TestEKS(t *testing.T) {
 		},
 	}).Once()
 
-	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything).Return(conn, nil).Once()
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
 
 	stsClient := new(mockSTSClient)
 	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
@@ -201,13 +290,102 @@ func TestEKS(t *testing.T) {
 
 	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
 
-	eksResourceDetector := resourceDetector{utils: utils}
+	eksResourceDetector := newResourceDetector(utils)
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster2"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func newMockTLSConn(names ...string) *mockTLSConn {
+	conn := new(mockTLSConn)
+	conn.On("Close").Return(nil).Once()
+	conn.On("ConnectionState").Return(tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			{
+				DNSNames: append(names,
+					"kubernetes",
+					"kubernetes.default",
+					"kubernetes.default.svc",
+					"kubernetes.default.svc.cluster.local",
+				),
+			},
+		},
+	}).Once()
+
+	return conn
+}
+
+func TestEKSEndpointMatcher(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"test-cluster1",
+			"test-cluster2",
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+		Name: aws.String("test-cluster1"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint: aws.String("https://DEF456.gr7.eu-west-1.eks.amazonaws.com"),
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+		Name: aws.String("test-cluster2"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint: aws.String("https://ABC123.gr7.eu-west-1.eks.amazonaws.com"),
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	// Match on the cluster ID portion of the endpoint only
+	matcher := func(certEndpoint, describeEndpoint string) bool {
+		certID, _, _ := strings.Cut(certEndpoint, ".")
+		describeID, _, _ := strings.Cut(strings.TrimPrefix(describeEndpoint, "https://"), ".")
+
+		return strings.EqualFold(certID, describeID)
+	}
+
+	eksResourceDetector := newResourceDetector(utils, WithEndpointMatcher(matcher))
 
 	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
 		semconv.CloudProviderAWS,
 		semconv.CloudPlatformAWSEKS,
 		semconv.CloudAccountID("0123456789012"),
 		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
 		semconv.K8SClusterName("test-cluster2"),
 	}...)
 
@@ -219,3 +397,3476 @@ func TestEKS(t *testing.T) {
 	conn.AssertExpectations(t)
 	eksClient.AssertExpectations(t)
 }
+
+func TestEndpointsMatch(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name  string
+		a, b  string
+		match bool
+	}{
+		{
+			name:  "scheme",
+			a:     "abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "https://abc123.gr7.eu-west-1.eks.amazonaws.com",
+			match: true,
+		},
+		{
+			name:  "both schemes",
+			a:     "https://abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "https://abc123.gr7.eu-west-1.eks.amazonaws.com",
+			match: true,
+		},
+		{
+			name:  "trailing slash",
+			a:     "abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "https://abc123.gr7.eu-west-1.eks.amazonaws.com/",
+			match: true,
+		},
+		{
+			name:  "mixed case",
+			a:     "abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "HTTPS://ABC123.gr7.eu-west-1.eks.amazonaws.com",
+			match: true,
+		},
+		{
+			name:  "different cluster",
+			a:     "abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "https://def456.gr7.eu-west-1.eks.amazonaws.com",
+			match: false,
+		},
+		{
+			name:  "different region",
+			a:     "abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "https://abc123.gr7.eu-west-2.eks.amazonaws.com",
+			match: false,
+		},
+		{
+			name:  "empty",
+			a:     "abc123.gr7.eu-west-1.eks.amazonaws.com",
+			b:     "",
+			match: false,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, table.match, endpointsMatch(table.a, table.b))
+			assert.Equal(t, table.match, endpointsMatch(table.b, table.a))
+		})
+	}
+}
+
+func TestEKSClusterNameEnv(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithClusterNameEnv("CLUSTER_NAME"))
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	utils.AssertNotCalled(t, "eksClient", mock.Anything)
+	conn.AssertExpectations(t)
+}
+
+func TestNotEKSOnEC2(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn()
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	imdsClient := new(mockIMDSClient)
+	imdsClient.On("GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything).Return(&imds.GetInstanceIdentityDocumentOutput{
+		InstanceIdentityDocument: imds.InstanceIdentityDocument{
+			AvailabilityZone: "eu-west-1a",
+			Region:           "eu-west-1",
+			InstanceID:       "i-0123456789abcdef0",
+			InstanceType:     "m7i.large",
+			AccountID:        "0123456789012",
+			ImageID:          "ami-0123456789abcdef0",
+		},
+	}, nil).Once()
+
+	utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithEC2Fallback(), WithClusterNameEnv("CLUSTER_NAME"))
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEC2,
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.CloudAvailabilityZone("eu-west-1a"),
+		semconv.CloudAccountID("0123456789012"),
+		semconv.HostID("i-0123456789abcdef0"),
+		semconv.HostType("m7i.large"),
+		semconv.HostImageID("ami-0123456789abcdef0"),
+		semconv.K8SClusterName("test-cluster"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	imdsClient.AssertExpectations(t)
+}
+
+func TestNotEKSNotEC2(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn()
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	imdsClient := new(mockIMDSClient)
+	imdsClient.On("GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded).Once()
+
+	utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithEC2Fallback())
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	imdsClient.AssertExpectations(t)
+}
+
+func TestEKSMaxClusters(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"test-cluster1",
+			"test-cluster2",
+			"test-cluster3",
+		},
+		NextToken: aws.String("token"),
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithMaxClusters(2))
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+	eksClient.AssertNotCalled(t, "DescribeCluster", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeDefault(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, semconv.SchemaURL, r.SchemaURL())
+
+	for _, other := range []*resource.Resource{
+		resource.Default(),
+		resource.Environment(),
+		resource.Empty(),
+	} {
+		merged, err := resource.Merge(other, r)
+		require.NoError(t, err)
+		assert.Equal(t, semconv.SchemaURL, merged.SchemaURL())
+	}
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+}
+
+func TestNetwork(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn()
+
+	utils.On("dial", mock.Anything, "tcp6", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithNetwork("tcp6"))
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+}
+
+func TestDetectEKS(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		dnsName   string
+		ok        bool
+		region    string
+		localZone string
+	}{
+		{
+			"standard",
+			"abc123.gr7.eu-west-1.eks.amazonaws.com",
+			true,
+			"eu-west-1",
+			"",
+		},
+		{
+			"legacy",
+			"abc123.yl4.us-east-1.eks.amazonaws.com",
+			true,
+			"us-east-1",
+			"",
+		},
+		{
+			"govcloud",
+			"abc123.gr7.us-gov-west-1.eks.amazonaws.com",
+			true,
+			"us-gov-west-1",
+			"",
+		},
+		{
+			"china",
+			"abc123.gr7.cn-north-1.api.amazonwebservices.com.cn",
+			true,
+			"cn-north-1",
+			"",
+		},
+		{
+			"outposts",
+			"abc123.us-west-2-sea-op-1.eks.amazonaws.com",
+			true,
+			"us-west-2",
+			"us-west-2-sea-op-1",
+		},
+		{
+			"local zone",
+			"abc123.us-east-1-bos-1a.eks.amazonaws.com",
+			true,
+			"us-east-1",
+			"us-east-1-bos-1a",
+		},
+		{
+			"wavelength",
+			"abc123.us-east-1-wl1-bos-wlz-1.eks.amazonaws.com",
+			true,
+			"us-east-1",
+			"us-east-1-wl1-bos-wlz-1",
+		},
+		{
+			"not eks",
+			"kubernetes.default.svc.cluster.local",
+			false,
+			"",
+			"",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			endpoint, region, ok := detectEKS([]string{table.dnsName})
+			require.Equal(t, table.ok, ok)
+
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, table.dnsName, endpoint)
+
+			region, localZone := splitLocalZone(region)
+			assert.Equal(t, table.region, region)
+			assert.Equal(t, table.localZone, localZone)
+		})
+	}
+}
+
+func TestClusterCandidatePrioritizer(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"dev",
+			"staging",
+			"prod-abc123",
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+		Name: aws.String("prod-abc123"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint: aws.String("https://ABC123.eu-west-1.eks.amazonaws.com"),
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	// Clusters are named with the endpoint ID as a suffix
+	prioritizer := func(clusters []string, endpoint string) []string {
+		id, _, _ := strings.Cut(endpoint, ".")
+
+		i := slices.IndexFunc(clusters, func(cluster string) bool {
+			return strings.HasSuffix(cluster, "-"+id)
+		})
+		if i > 0 {
+			cluster := clusters[i]
+			clusters = append([]string{cluster}, slices.Delete(clusters, i, i+1)...)
+		}
+
+		return clusters
+	}
+
+	eksResourceDetector := newResourceDetector(utils, WithClusterCandidatePrioritizer(prioritizer))
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("prod-abc123"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+	eksClient.AssertNumberOfCalls(t, "DescribeCluster", 1)
+}
+
+func TestRegionFromIMDS(t *testing.T) {
+	t.Parallel()
+
+	config := &rest.Config{Host: testHost}
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(config, nil).Once()
+
+	conn := newMockTLSConn("api.k8s.example.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+	utils.On("lookupEnv", NodeNameEnv).Return("ip-10-0-0-1.eu-west-1.compute.internal", true).Once()
+	utils.On("nodeLabels", mock.Anything, config, "ip-10-0-0-1.eu-west-1.compute.internal").Return(map[string]string{
+		"kubernetes.io/os":               "linux",
+		"eks.amazonaws.com/nodegroup":    "ng-1",
+		"eks.amazonaws.com/capacityType": "ON_DEMAND",
+	}, nil).Once()
+
+	imdsClient := new(mockIMDSClient)
+	imdsClient.On("GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything).Return(&imds.GetInstanceIdentityDocumentOutput{
+		InstanceIdentityDocument: imds.InstanceIdentityDocument{
+			Region: "eu-west-1",
+		},
+	}, nil).Once()
+
+	utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"test-cluster",
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithRegionFromIMDS())
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	imdsClient.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func TestRegionFromIMDSNotEKSNode(t *testing.T) {
+	t.Parallel()
+
+	config := &rest.Config{Host: testHost}
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(config, nil).Once()
+
+	conn := newMockTLSConn("api.k8s.example.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+	utils.On("lookupEnv", NodeNameEnv).Return("node1", true).Once()
+	utils.On("nodeLabels", mock.Anything, config, "node1").Return(map[string]string{
+		"kubernetes.io/os": "linux",
+	}, nil).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithRegionFromIMDS())
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+	utils.AssertNotCalled(t, "imdsClient", mock.Anything)
+	conn.AssertExpectations(t)
+}
+
+func TestNodeLabels(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/nodes/node1", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"Node","apiVersion":"v1","metadata":{"name":"node1","labels":{"eks.amazonaws.com/nodegroup":"ng-1"}}}`))
+	}))
+	defer server.Close()
+
+	utils := new(eksDetectorUtils)
+
+	labels, err := utils.nodeLabels(t.Context(), &rest.Config{Host: server.URL}, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"eks.amazonaws.com/nodegroup": "ng-1"}, labels)
+}
+
+func TestResultTransformer(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name        string
+		transformer func(*resource.Resource) (*resource.Resource, error)
+		expected    *resource.Resource
+		err         error
+	}{
+		{
+			"derived attribute",
+			func(r *resource.Resource) (*resource.Resource, error) {
+				region, _ := r.Set().Value(semconv.CloudRegionKey)
+
+				return resource.Merge(r, resource.NewWithAttributes(semconv.SchemaURL,
+					attribute.String("region.alias", strings.ToUpper(region.AsString())),
+				))
+			},
+			resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+				attribute.String("region.alias", "EU-WEST-1"),
+			}...),
+			nil,
+		},
+		{
+			"error",
+			func(*resource.Resource) (*resource.Resource, error) {
+				return nil, errTest
+			},
+			nil,
+			errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils, WithResultTransformer(table.transformer))
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+			conn.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		dnsName   string
+		region    string
+		partition string
+	}{
+		{
+			"standard",
+			"abc123.gr7.eu-west-1.eks.amazonaws.com",
+			"eu-west-1",
+			"aws",
+		},
+		{
+			"china",
+			"abc123.gr7.cn-northwest-1.api.amazonwebservices.com.cn",
+			"cn-northwest-1",
+			"aws-cn",
+		},
+		{
+			"govcloud",
+			"abc123.gr7.us-gov-west-1.eks.amazonaws.com",
+			"us-gov-west-1",
+			"aws-us-gov",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn(table.dnsName)
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils)
+
+			expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudRegion(table.region),
+				partitionKey.String(table.partition),
+			}...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, expected, r)
+
+			utils.AssertExpectations(t)
+			conn.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConnectionState(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := new(mockTLSConn)
+	conn.On("Close").Return(nil).Once()
+	conn.On("ConnectionState").Return(tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		NegotiatedProtocol: "h2",
+		PeerCertificates: []*x509.Certificate{
+			{
+				DNSNames: []string{
+					"kubernetes",
+				},
+			},
+		},
+	}).Once()
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	eksResourceDetector := newResourceDetector(utils)
+
+	_, ok := ConnectionState(eksResourceDetector)
+	assert.False(t, ok)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	state, ok := ConnectionState(eksResourceDetector)
+	require.True(t, ok)
+	assert.Equal(t, uint16(tls.VersionTLS13), state.Version)
+	assert.Equal(t, tls.TLS_AES_128_GCM_SHA256, state.CipherSuite)
+	assert.Equal(t, "h2", state.NegotiatedProtocol)
+
+	_, ok = ConnectionState(resource.StringDetector(semconv.SchemaURL, semconv.HostNameKey, func() (string, error) {
+		return "test", nil
+	}))
+	assert.False(t, ok)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+}
+
+func TestSkipRegions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithSkipRegions("us-east-1", "eu-west-1"))
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+	utils.AssertNotCalled(t, "stsClient", mock.Anything)
+	utils.AssertNotCalled(t, "eksClient", mock.Anything)
+	conn.AssertExpectations(t)
+}
+
+func TestRegionAlias(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		aliases  map[string]string
+		expected string
+	}{
+		{
+			name: "alias",
+			aliases: map[string]string{
+				"eu-west-1": "dublin",
+				"us-east-1": "virginia",
+			},
+			expected: "dublin",
+		},
+		{
+			name: "unmapped",
+			aliases: map[string]string{
+				"us-east-1": "virginia",
+			},
+			expected: "eu-west-1",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksClient := new(mockEKSClient)
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameSources(StaticClusterName("test-cluster")),
+				WithRegionAlias(table.aliases),
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion(table.expected),
+				partitionKey.String("aws"),
+				semconv.K8SClusterName("test-cluster"),
+			), r)
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDescribeEKSCluster(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		fields   ClusterField
+		expected *clusterInfo
+	}{
+		{
+			name:   "minimal",
+			fields: 0,
+			expected: &clusterInfo{
+				endpoint: "https://ABC123.eu-west-1.eks.amazonaws.com",
+			},
+		},
+		{
+			name:   "version",
+			fields: ClusterVersion,
+			expected: &clusterInfo{
+				endpoint: "https://ABC123.eu-west-1.eks.amazonaws.com",
+				version:  "1.33",
+			},
+		},
+		{
+			name:   "full",
+			fields: ClusterARN | ClusterVersion | ClusterTags,
+			expected: &clusterInfo{
+				endpoint: "https://ABC123.eu-west-1.eks.amazonaws.com",
+				arn:      "arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster",
+				version:  "1.33",
+				tags: map[string]string{
+					"team": "platform",
+				},
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			eksClient := new(mockEKSClient)
+			eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+				Name: aws.String("test-cluster"),
+			}, mock.Anything).Return(&eks.DescribeClusterOutput{
+				Cluster: &ekstypes.Cluster{
+					Arn:      aws.String("arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster"),
+					Endpoint: aws.String("https://ABC123.eu-west-1.eks.amazonaws.com"),
+					Version:  aws.String("1.33"),
+					Tags: map[string]string{
+						"team": "platform",
+					},
+				},
+			}, nil).Once()
+
+			info, err := describeEKSCluster(t.Context(), eksClient, "test-cluster", table.fields)
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, info)
+
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDescribeClusterFields(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	// With only one cluster it is still described to get the extra fields
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"test-cluster",
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+		Name: aws.String("test-cluster"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Arn:      aws.String("arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster"),
+			Endpoint: aws.String("https://ABC123.eu-west-1.eks.amazonaws.com"),
+			Version:  aws.String("1.33"),
+			Tags: map[string]string{
+				"team": "platform",
+				"env":  "prod",
+			},
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithDescribeClusterFields(ClusterARN, ClusterTags))
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+		semconv.AWSEKSClusterARN("arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster"),
+		attribute.String("aws.eks.cluster.tag.env", "prod"),
+		attribute.String("aws.eks.cluster.tag.team", "platform"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func TestPartialOnError(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		options  []Option
+		expected *resource.Resource
+		err      error
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "partial",
+			options: []Option{
+				WithPartialOnError(),
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+			}...),
+		},
+		{
+			name: "partial with transformer error",
+			options: []Option{
+				WithPartialOnError(),
+				WithResultTransformer(func(*resource.Resource) (*resource.Resource, error) {
+					return nil, errTest
+				}),
+			},
+			// The partial resource is kept and both errors are returned
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+			}...),
+			err: errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksClient := new(mockEKSClient)
+			eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+				Clusters: []string{
+					"test-cluster1",
+					"test-cluster2",
+				},
+			}, nil).Once()
+			eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+				Name: aws.String("test-cluster1"),
+			}, mock.Anything).Return(nil, new(ekstypes.ServerException)).Once()
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils, table.options...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.ErrorAs(t, err, new(*ekstypes.ServerException))
+
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+			}
+
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultThrottleRetryer(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name        string
+		maxAttempts int
+		err         error
+		attempts    int
+	}{
+		{
+			name:        "throttled",
+			maxAttempts: 3,
+			err: &smithy.GenericAPIError{
+				Code: "Throttling",
+			},
+			attempts: 3,
+		},
+		{
+			name:        "throttled more attempts",
+			maxAttempts: 5,
+			err: &smithy.GenericAPIError{
+				Code: "ThrottlingException",
+			},
+			attempts: 5,
+		},
+		{
+			name:        "access denied",
+			maxAttempts: 3,
+			err: &smithy.GenericAPIError{
+				Code: "AccessDeniedException",
+			},
+			attempts: 1,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			retryer := DefaultThrottleRetryer(table.maxAttempts)()
+			assert.Equal(t, table.maxAttempts, retryer.MaxAttempts())
+
+			// Follow the same steps as the SDK retry middleware
+			attempts := 1
+			for retryer.IsErrorRetryable(table.err) && attempts < retryer.MaxAttempts() {
+				_, err := retryer.GetRetryToken(t.Context(), table.err)
+				require.NoError(t, err)
+
+				delay, err := retryer.RetryDelay(attempts, table.err)
+				require.NoError(t, err)
+				assert.GreaterOrEqual(t, delay, time.Duration(0))
+
+				attempts++
+			}
+
+			assert.Equal(t, table.attempts, attempts)
+		})
+	}
+}
+
+func TestRetryer(t *testing.T) {
+	t.Parallel()
+
+	eksResourceDetector := newResourceDetector(nil)
+	assert.IsType(t, new(aws.NopRetryer), eksResourceDetector.retryer())
+
+	eksResourceDetector = newResourceDetector(nil, WithRetryer(DefaultThrottleRetryer(3)))
+	assert.Equal(t, 3, eksResourceDetector.retryer().MaxAttempts())
+}
+
+func TestRegionFromAWSConfig(t *testing.T) {
+	t.Parallel()
+
+	config := &rest.Config{Host: testHost}
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(config, nil).Once()
+
+	conn := newMockTLSConn("api.k8s.example.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+	utils.On("lookupEnv", NodeNameEnv).Return("ip-10-0-0-1.eu-west-2.compute.internal", true).Once()
+	utils.On("nodeLabels", mock.Anything, config, "ip-10-0-0-1.eu-west-2.compute.internal").Return(map[string]string{
+		"eks.amazonaws.com/nodegroup": "ng-1",
+	}, nil).Once()
+
+	imdsClient := new(mockIMDSClient)
+	imdsClient.On("GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything).Return(nil, errTest).Once()
+
+	utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-2:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithRegionFromIMDS(),
+		WithAWSConfig(aws.Config{Region: "eu-west-2"}),
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithWarningHandler(func(err error) {
+			assert.Fail(t, "unexpected warning", err)
+		}),
+	)
+
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-2"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	imdsClient.AssertExpectations(t)
+}
+
+func TestRegionMismatch(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	var warnings []error
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithAWSConfig(aws.Config{Region: "us-east-1"}),
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithWarningHandler(func(err error) {
+			warnings = append(warnings, err)
+		}),
+	)
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], errRegionMismatch)
+
+	utils.AssertExpectations(t)
+}
+
+//nolint:paralleltest // Modifies the environment
+func TestRegionMismatchDefaultConfig(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	// A deliberately different AWS_REGION isn't a problem
+	eksResourceDetector := newResourceDetector(utils,
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithWarningHandler(func(err error) {
+			assert.Fail(t, "unexpected warning", err)
+		}),
+	)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestClusterNameCache(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Twice()
+
+	conn1 := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+	conn2 := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn1, nil).Once()
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn2, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Twice()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Twice()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"test-cluster1",
+			"test-cluster2",
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+		Name: aws.String("test-cluster1"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint: aws.String("https://ABC123.eu-west-1.eks.amazonaws.com"),
+		},
+	}, nil).Once()
+
+	// Only the first detection uses the EKS API
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	cache := NewCache()
+
+	eksResourceDetector := newResourceDetector(utils, WithClusterNameCache(cache))
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster1"),
+	}...)
+
+	for range 2 {
+		r, err := eksResourceDetector.Detect(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, expected, r)
+	}
+
+	clusterName, ok := cache.Get("abc123.eu-west-1.eks.amazonaws.com")
+	assert.True(t, ok)
+	assert.Equal(t, "test-cluster1", clusterName)
+
+	utils.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func TestCustomAttributePrefix(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.us-east-1-bos-1a.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:us-east-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+		Name: aws.String("test-cluster"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Arn:      aws.String("arn:aws:eks:us-east-1:0123456789012:cluster/test-cluster"),
+			Endpoint: aws.String("https://ABC123.us-east-1-bos-1a.eks.amazonaws.com"),
+			Version:  aws.String("1.33"),
+			Tags: map[string]string{
+				"team": "platform",
+			},
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithDescribeClusterFields(ClusterARN, ClusterVersion, ClusterTags),
+		WithCustomAttributePrefix("acme."),
+	)
+
+	// Only the custom keys are prefixed
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("us-east-1"),
+		attribute.String("acme.aws.partition", "aws"),
+		attribute.String("acme.aws.local_zone", "us-east-1-bos-1a"),
+		semconv.K8SClusterName("test-cluster"),
+		semconv.AWSEKSClusterARN("arn:aws:eks:us-east-1:0123456789012:cluster/test-cluster"),
+		attribute.String("acme.aws.eks.cluster.version", "1.33"),
+		attribute.String("acme.aws.eks.cluster.tag.team", "platform"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func TestAWSClientLogMode(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		options  []Option
+		expected aws.ClientLogMode
+	}{
+		{
+			name: "default",
+			options: []Option{
+				WithAWSConfig(aws.Config{Region: "eu-west-1"}),
+			},
+		},
+		{
+			name: "requests and responses",
+			options: []Option{
+				WithAWSConfig(aws.Config{Region: "eu-west-1"}),
+				WithAWSClientLogMode(aws.LogRequest | aws.LogResponse | aws.LogRetries),
+			},
+			expected: aws.LogRequest | aws.LogResponse | aws.LogRetries,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(eksDetectorUtils)
+
+			eksResourceDetector := newResourceDetector(utils, table.options...)
+
+			awsConfig, err := eksResourceDetector.loadAWSConfig(t.Context())
+			require.NoError(t, err)
+
+			stsClient, ok := utils.stsClient(awsConfig).(*sts.Client)
+			require.True(t, ok)
+			assert.Equal(t, table.expected, stsClient.Options().ClientLogMode)
+
+			eksClient, ok := utils.eksClient(awsConfig).(*eks.Client)
+			require.True(t, ok)
+			assert.Equal(t, table.expected, eksClient.Options().ClientLogMode)
+		})
+	}
+}
+
+func TestDialNetTimeout(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		options  []Option
+		expected time.Duration
+	}{
+		{
+			name:     "default",
+			expected: 5 * time.Second,
+		},
+		{
+			name: "custom",
+			options: []Option{
+				WithDialNetTimeout(100 * time.Millisecond),
+			},
+			expected: 100 * time.Millisecond,
+		},
+		{
+			name: "caller context only",
+			options: []Option{
+				WithRespectCallerContextOnly(),
+			},
+			expected: 0,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, table.expected).Return(nil, errTest).Once()
+
+			eksResourceDetector := newResourceDetector(utils, table.options...)
+
+			_, err := eksResourceDetector.Detect(t.Context())
+			require.ErrorIs(t, err, errTest)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDialHandshakeTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Accept connections but never complete the TLS handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		<-t.Context().Done()
+		conn.Close()
+	}()
+
+	start := time.Now()
+
+	_, err = new(eksDetectorUtils).dial(t.Context(), "tcp", listener.Addr().String(), &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}, 100*time.Millisecond)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGetAccountID(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		output    *sts.GetCallerIdentityOutput
+		validator func(arn.ARN) error
+		accountID string
+		err       error
+	}{
+		{
+			name: "arn",
+			output: &sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:sts::0123456789012:assumed-role/test/session"),
+			},
+			accountID: "0123456789012",
+		},
+		{
+			name: "nil arn",
+			output: &sts.GetCallerIdentityOutput{
+				Account: aws.String("0123456789012"),
+			},
+			accountID: "0123456789012",
+		},
+		{
+			name:   "nil arn and account",
+			output: new(sts.GetCallerIdentityOutput),
+			err:    errMissingARN,
+		},
+		{
+			name: "malformed arn",
+			output: &sts.GetCallerIdentityOutput{
+				Arn:     aws.String("not-an-arn"),
+				Account: aws.String("0123456789012"),
+			},
+		},
+		{
+			name: "arn without account",
+			output: &sts.GetCallerIdentityOutput{
+				Arn:     aws.String("arn:aws:sts:::assumed-role/test/session"),
+				Account: aws.String("0123456789012"),
+			},
+			accountID: "0123456789012",
+		},
+		{
+			name: "no account",
+			output: &sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:sts:::assumed-role/test/session"),
+			},
+			err: errMissingAccountID,
+		},
+		{
+			name: "validator",
+			output: &sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam::0123456789012:user/test"),
+			},
+			validator: func(a arn.ARN) error {
+				if !strings.HasPrefix(a.Resource, "assumed-role/") {
+					return errTest
+				}
+
+				return nil
+			},
+			err: errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(table.output, nil).Once()
+
+			accountID, err := getAccountID(t.Context(), stsClient, table.validator)
+
+			switch {
+			case table.err != nil:
+				require.ErrorIs(t, err, table.err)
+			case table.accountID == "":
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, table.accountID, accountID)
+
+			stsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestARNValidator(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws-cn:iam::0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithARNValidator(func(a arn.ARN) error {
+		if a.Partition != "aws" {
+			return errTest
+		}
+
+		return nil
+	}))
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestFallbackAccountID(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		err       error
+		validator func(arn.ARN) error
+		accountID string
+		expected  error
+	}{
+		{
+			name:      "sts timeout",
+			err:       context.DeadlineExceeded,
+			accountID: "210987654321",
+		},
+		{
+			name:      "sts denied",
+			err:       errTest,
+			accountID: "210987654321",
+		},
+		{
+			name:      "sts success",
+			accountID: "0123456789012",
+		},
+		{
+			name: "invalid arn",
+			validator: func(arn.ARN) error {
+				return errTest
+			},
+			expected: errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+
+			if table.err != nil {
+				stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(nil, table.err).Once()
+			} else {
+				stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+					Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+				}, nil).Once()
+			}
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			if table.expected == nil {
+				utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+			}
+
+			options := []Option{
+				WithClusterNameEnv("CLUSTER_NAME"),
+				WithFallbackAccountID("210987654321"),
+			}
+
+			if table.validator != nil {
+				options = append(options, WithARNValidator(table.validator))
+			}
+
+			eksResourceDetector := newResourceDetector(utils, options...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			if table.expected != nil {
+				// The fallback doesn't hide a rejected ARN
+				require.ErrorIs(t, err, table.expected)
+				assert.Nil(t, r)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+					semconv.CloudProviderAWS,
+					semconv.CloudPlatformAWSEKS,
+					semconv.CloudAccountID(table.accountID),
+					semconv.CloudRegion("eu-west-1"),
+					partitionKey.String("aws"),
+					semconv.K8SClusterName("test-cluster"),
+				}...), r)
+			}
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFingerprintAttribute(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Times(3)
+
+	// Return the SANs in a different order the second time
+	conn1 := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+	conn2 := new(mockTLSConn)
+	conn2.On("Close").Return(nil).Once()
+	conn2.On("ConnectionState").Return(tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			{
+				DNSNames: []string{
+					"kubernetes",
+					"abc123.eu-west-1.eks.amazonaws.com",
+				},
+			},
+		},
+	}).Once()
+	conn3 := newMockTLSConn("def456.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn1, nil).Once()
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn2, nil).Once()
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn3, nil).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Twice()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("other-cluster", true).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Times(3)
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Times(3)
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithFingerprintAttribute("resource.fingerprint"),
+	)
+
+	fingerprints := make([]string, 0, 3)
+
+	for range 3 {
+		r, err := eksResourceDetector.Detect(t.Context())
+		require.NoError(t, err)
+
+		v, ok := r.Set().Value("resource.fingerprint")
+		require.True(t, ok)
+		assert.Len(t, v.AsString(), sha256.Size*2)
+
+		fingerprints = append(fingerprints, v.AsString())
+	}
+
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+
+	utils.AssertExpectations(t)
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		config   *rest.Config
+		options  []Option
+		expected uint16
+	}{
+		{
+			name: "default",
+			config: &rest.Config{
+				Host: testHost,
+				TLSClientConfig: rest.TLSClientConfig{
+					Insecure: true,
+				},
+			},
+			expected: tls.VersionTLS12,
+		},
+		{
+			name: "tls 1.3",
+			config: &rest.Config{
+				Host: testHost,
+				TLSClientConfig: rest.TLSClientConfig{
+					Insecure: true,
+				},
+			},
+			options: []Option{
+				WithTLSMinVersion(tls.VersionTLS13),
+			},
+			expected: tls.VersionTLS13,
+		},
+		{
+			name: "no tls config",
+			config: &rest.Config{
+				Host: testHost,
+			},
+			options: []Option{
+				WithTLSMinVersion(tls.VersionTLS13),
+			},
+			expected: tls.VersionTLS13,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(table.config, nil).Once()
+
+			conn := newMockTLSConn()
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.MatchedBy(func(config *tls.Config) bool {
+				return config != nil && config.MinVersion == table.expected
+			}), mock.Anything).Return(conn, nil).Once()
+
+			eksResourceDetector := newResourceDetector(utils, table.options...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.Empty(), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCloudPlatformOverride(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithCloudPlatformOverride(semconv.CloudPlatformKey.String("aws_eks_anywhere")),
+	)
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformKey.String("aws_eks_anywhere"),
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+}
+
+func TestDetectorNameAttribute(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithDetectorNameAttribute("otel.resource.detector"),
+	)
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.K8SClusterName("test-cluster"),
+		attribute.String("otel.resource.detector", "aws.eks"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	conn.AssertExpectations(t)
+}
+
+func TestDetectorNameAttributeNotEKS(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(nil, rest.ErrNotInCluster).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithDetectorNameAttribute("otel.resource.detector"))
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestRespectCallerContextOnly(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		options  []Option
+		deadline bool
+	}{
+		{
+			name:     "default",
+			deadline: true,
+		},
+		{
+			name: "caller context only",
+			options: []Option{
+				WithRespectCallerContextOnly(),
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+			utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.MatchedBy(func(ctx context.Context) bool {
+				_, ok := ctx.Deadline()
+
+				return ok == table.deadline
+			}), mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				append([]Option{WithClusterNameEnv("CLUSTER_NAME")}, table.options...)...,
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+
+			v, ok := r.Set().Value(semconv.CloudAccountIDKey)
+			require.True(t, ok)
+			assert.Equal(t, "0123456789012", v.AsString())
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClusterNameFromTag(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		tags     map[string]string
+		options  []Option
+		expected []attribute.KeyValue
+	}{
+		{
+			name: "tag present",
+			tags: map[string]string{
+				"Name": "production",
+				"team": "platform",
+			},
+			expected: []attribute.KeyValue{
+				semconv.K8SClusterName("production"),
+			},
+		},
+		{
+			name: "tag absent",
+			tags: map[string]string{
+				"team": "platform",
+			},
+			expected: []attribute.KeyValue{
+				semconv.K8SClusterName("test-cluster"),
+			},
+		},
+		{
+			name: "with tags",
+			tags: map[string]string{
+				"Name": "production",
+				"team": "platform",
+			},
+			options: []Option{
+				WithDescribeClusterFields(ClusterTags),
+			},
+			expected: []attribute.KeyValue{
+				semconv.K8SClusterName("production"),
+				attribute.String("aws.eks.cluster.tag.Name", "production"),
+				attribute.String("aws.eks.cluster.tag.team", "platform"),
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+			utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksClient := new(mockEKSClient)
+			eksClient.On("DescribeCluster", mock.Anything, &eks.DescribeClusterInput{
+				Name: aws.String("test-cluster"),
+			}, mock.Anything).Return(&eks.DescribeClusterOutput{
+				Cluster: &ekstypes.Cluster{
+					Endpoint: aws.String("https://ABC123.eu-west-1.eks.amazonaws.com"),
+					Tags:     table.tags,
+				},
+			}, nil).Once()
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				append([]Option{
+					WithClusterNameEnv("CLUSTER_NAME"),
+					WithClusterNameFromTag("Name"),
+				}, table.options...)...,
+			)
+
+			expected := resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+			}, table.expected...)...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, expected, r)
+
+			utils.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAmbiguousClusterAttribute(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		options  []Option
+		expected []attribute.KeyValue
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "candidates",
+			options: []Option{
+				WithAmbiguousClusterAttribute(),
+			},
+			expected: []attribute.KeyValue{
+				candidatesKey.String("test-cluster1,test-cluster2"),
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksClient := new(mockEKSClient)
+			eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+				Clusters: []string{
+					"test-cluster1",
+					"test-cluster2",
+				},
+			}, nil).Once()
+			eksClient.On("DescribeCluster", mock.Anything, mock.Anything, mock.Anything).Return(nil, new(ekstypes.AccessDeniedException)).Twice()
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils, table.options...)
+
+			expected := resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+			}, table.expected...)...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, expected, r)
+
+			utils.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"test-cluster",
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils,
+		WithAWSConfig(aws.Config{Region: "eu-west-1"}),
+		WithEKSOptions(func(o *eks.Options) {
+			o.AppID = "eks-app"
+		}),
+		WithSTSOptions(func(o *sts.Options) {
+			o.AppID = "sts-app"
+		}, func(o *sts.Options) {
+			o.Region = "us-east-1"
+		}),
+	)
+
+	_, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	var stsOptions sts.Options
+	for _, fn := range utils.stsOptions {
+		fn(&stsOptions)
+	}
+
+	assert.Equal(t, "sts-app", stsOptions.AppID)
+	assert.Equal(t, "us-east-1", stsOptions.Region)
+
+	var eksOptions eks.Options
+	for _, fn := range utils.eksOptions {
+		fn(&eksOptions)
+	}
+
+	assert.Equal(t, "eks-app", eksOptions.AppID)
+
+	utils.AssertExpectations(t)
+	stsClient.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		lifecycle string
+		err       error
+		expected  []attribute.KeyValue
+	}{
+		{
+			name:      "spot",
+			lifecycle: "spot",
+			expected: []attribute.KeyValue{
+				lifecycleKey.String("spot"),
+			},
+		},
+		{
+			name:      "on-demand",
+			lifecycle: "on-demand\n",
+			expected: []attribute.KeyValue{
+				lifecycleKey.String("on-demand"),
+			},
+		},
+		{
+			name: "no response",
+			err:  context.DeadlineExceeded,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+			utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+			imdsClient := new(mockIMDSClient)
+
+			if table.err != nil {
+				imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+					Path: "instance-life-cycle",
+				}, mock.Anything).Return(nil, table.err).Once()
+			} else {
+				imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+					Path: "instance-life-cycle",
+				}, mock.Anything).Return(&imds.GetMetadataOutput{
+					Content: io.NopCloser(strings.NewReader(table.lifecycle)),
+				}, nil).Once()
+			}
+
+			utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameEnv("CLUSTER_NAME"),
+				WithInstanceLifecycle(),
+			)
+
+			expected := resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+				semconv.K8SClusterName("test-cluster"),
+			}, table.expected...)...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, expected, r)
+
+			utils.AssertExpectations(t)
+			imdsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInstanceLifecycleOnEC2(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn()
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	imdsClient := new(mockIMDSClient)
+	imdsClient.On("GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything).Return(&imds.GetInstanceIdentityDocumentOutput{
+		InstanceIdentityDocument: imds.InstanceIdentityDocument{
+			AvailabilityZone: "eu-west-1a",
+			Region:           "eu-west-1",
+			InstanceID:       "i-0123456789abcdef0",
+			InstanceType:     "m7i.large",
+			AccountID:        "0123456789012",
+			ImageID:          "ami-0123456789abcdef0",
+		},
+	}, nil).Once()
+	imdsClient.On("GetMetadata", mock.Anything, mock.Anything, mock.Anything).Return(&imds.GetMetadataOutput{
+		Content: io.NopCloser(strings.NewReader("spot")),
+	}, nil).Once()
+
+	utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithEC2Fallback(), WithInstanceLifecycle())
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEC2,
+		semconv.CloudRegion("eu-west-1"),
+		partitionKey.String("aws"),
+		semconv.CloudAvailabilityZone("eu-west-1a"),
+		semconv.CloudAccountID("0123456789012"),
+		semconv.HostID("i-0123456789abcdef0"),
+		semconv.HostType("m7i.large"),
+		semconv.HostImageID("ami-0123456789abcdef0"),
+		lifecycleKey.String("spot"),
+	}...)
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, expected, r)
+
+	utils.AssertExpectations(t)
+	imdsClient.AssertExpectations(t)
+}
+
+func TestSortedAttributes(t *testing.T) {
+	t.Parallel()
+
+	options := []Option{
+		WithClusterNameEnv("CLUSTER_NAME"),
+		WithCustomAttributePrefix("acme."),
+		WithDetectorNameAttribute("otel.resource.detector"),
+		WithFingerprintAttribute("resource.fingerprint"),
+		WithCloudPlatformOverride(semconv.CloudPlatformKey.String("aws_eks_anywhere")),
+	}
+
+	reversed := slices.Clone(options)
+	slices.Reverse(reversed)
+
+	encoded := make([]string, 0, 2)
+
+	for _, options := range [][]Option{options, reversed} {
+		utils := new(mockDetectorUtils)
+		utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+		conn := newMockTLSConn("abc123.us-east-1-bos-1a.eks.amazonaws.com")
+
+		utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+		utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+		stsClient := new(mockSTSClient)
+		stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+			Arn: aws.String("arn:aws:iam:us-east-1:0123456789012:role/test"),
+		}, nil).Once()
+
+		utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+		eksResourceDetector := newResourceDetector(utils, options...)
+
+		r, err := eksResourceDetector.Detect(t.Context())
+		require.NoError(t, err)
+
+		keys := make([]string, 0, r.Len())
+		for _, kv := range r.Attributes() {
+			keys = append(keys, string(kv.Key))
+		}
+
+		assert.True(t, slices.IsSorted(keys))
+
+		encoded = append(encoded, r.Encoded(attribute.DefaultEncoder()))
+
+		utils.AssertExpectations(t)
+	}
+
+	assert.Equal(t, encoded[0], encoded[1])
+}
+
+// newTestCertificate returns a self-signed certificate standing in for the
+// AWS public certificate, and its private key.
+func newTestCertificate(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			Organization: []string{"Amazon Web Services LLC"},
+		},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	}
+
+	b, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(b)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func TestInstanceIdentityVerification(t *testing.T) {
+	t.Parallel()
+
+	cert, key := newTestCertificate(t)
+	other, _ := newTestCertificate(t)
+
+	document := []byte(`{
+  "accountId" : "0123456789012",
+  "availabilityZone" : "eu-west-1a",
+  "imageId" : "ami-0123456789abcdef0",
+  "instanceId" : "i-0123456789abcdef0",
+  "instanceType" : "m7i.large",
+  "region" : "eu-west-1"
+}`)
+
+	digest := sha256.Sum256(document)
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	tampered := bytes.Replace(document, []byte("0123456789012"), []byte("9876543210987"), 1)
+
+	tables := []struct {
+		name     string
+		document []byte
+		certs    []*x509.Certificate
+		expected *resource.Resource
+	}{
+		{
+			name:     "valid",
+			document: document,
+			certs:    []*x509.Certificate{other, cert},
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEC2,
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+				semconv.CloudAvailabilityZone("eu-west-1a"),
+				semconv.CloudAccountID("0123456789012"),
+				semconv.HostID("i-0123456789abcdef0"),
+				semconv.HostType("m7i.large"),
+				semconv.HostImageID("ami-0123456789abcdef0"),
+			}...),
+		},
+		{
+			name:     "tampered",
+			document: tampered,
+			certs:    []*x509.Certificate{cert},
+			expected: resource.Empty(),
+		},
+		{
+			name:     "wrong certificate",
+			document: document,
+			certs:    []*x509.Certificate{other},
+			expected: resource.Empty(),
+		},
+		{
+			name:     "no certificates",
+			document: document,
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn()
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			imdsClient := new(mockIMDSClient)
+			imdsClient.On("GetDynamicData", mock.Anything, &imds.GetDynamicDataInput{
+				Path: "instance-identity/document",
+			}, mock.Anything).Return(&imds.GetDynamicDataOutput{
+				Content: io.NopCloser(bytes.NewReader(table.document)),
+			}, nil).Once()
+			imdsClient.On("GetDynamicData", mock.Anything, &imds.GetDynamicDataInput{
+				Path: "instance-identity/signature",
+			}, mock.Anything).Return(&imds.GetDynamicDataOutput{
+				Content: io.NopCloser(strings.NewReader(base64.StdEncoding.EncodeToString(signature) + "\n")),
+			}, nil).Once()
+
+			utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+			var warnings []error
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithEC2Fallback(),
+				WithInstanceIdentityVerification(table.certs...),
+				WithWarningHandler(func(err error) {
+					warnings = append(warnings, err)
+				}),
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			if table.expected.Len() == 0 {
+				require.Len(t, warnings, 1)
+				assert.ErrorIs(t, warnings[0], errUnverified)
+			} else {
+				assert.Empty(t, warnings)
+			}
+
+			utils.AssertExpectations(t)
+			imdsClient.AssertExpectations(t)
+			imdsClient.AssertNotCalled(t, "GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMaxAttributes(t *testing.T) {
+	t.Parallel()
+
+	tags := make(map[string]string, 50)
+	for i := range 50 {
+		tags[fmt.Sprintf("tag%02d", i)] = "value"
+	}
+
+	core := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudRegion("eu-west-1"),
+	}
+
+	tables := []struct {
+		name     string
+		max      int
+		options  []Option
+		expected []attribute.KeyValue
+	}{
+		{
+			name:     "core only",
+			max:      3,
+			expected: core,
+		},
+		{
+			name: "some tags",
+			max:  10,
+			expected: []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudRegion("eu-west-1"),
+				semconv.CloudAccountID("0123456789012"),
+				semconv.K8SClusterName("test-cluster"),
+				semconv.AWSEKSClusterARN("arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster"),
+				partitionKey.String("aws"),
+				clusterVersionKey.String("1.33"),
+				attribute.String("aws.eks.cluster.tag.tag00", "value"),
+				attribute.String("aws.eks.cluster.tag.tag01", "value"),
+			},
+		},
+		{
+			name: "instance tags",
+			max:  8,
+			options: []Option{
+				WithInstanceTags("team"),
+			},
+			// The instance tags rank with the cluster tags, so are dropped first
+			expected: []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudRegion("eu-west-1"),
+				semconv.CloudAccountID("0123456789012"),
+				semconv.K8SClusterName("test-cluster"),
+				semconv.AWSEKSClusterARN("arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster"),
+				partitionKey.String("aws"),
+				clusterVersionKey.String("1.33"),
+			},
+		},
+		{
+			name: "fingerprint and name",
+			max:  5,
+			options: []Option{
+				WithFingerprintAttribute("resource.fingerprint"),
+				WithDetectorNameAttribute("otel.resource.detector"),
+			},
+			// The fingerprint only covers the attributes that are kept
+			expected: append(slices.Clone(core),
+				fingerprint(resource.NewWithAttributes(semconv.SchemaURL, core...), "resource.fingerprint"),
+				attribute.String("otel.resource.detector", "aws.eks"),
+			),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+			utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksClient := new(mockEKSClient)
+			eksClient.On("DescribeCluster", mock.Anything, mock.Anything, mock.Anything).Return(&eks.DescribeClusterOutput{
+				Cluster: &ekstypes.Cluster{
+					Arn:      aws.String("arn:aws:eks:eu-west-1:0123456789012:cluster/test-cluster"),
+					Endpoint: aws.String("https://ABC123.eu-west-1.eks.amazonaws.com"),
+					Version:  aws.String("1.33"),
+					Tags:     tags,
+				},
+			}, nil).Once()
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			imdsClient := new(mockIMDSClient)
+			imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+				Path: "tags/instance",
+			}, mock.Anything).Return(&imds.GetMetadataOutput{
+				Content: io.NopCloser(strings.NewReader("Name\nteam")),
+			}, nil).Maybe()
+			imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+				Path: "tags/instance/team",
+			}, mock.Anything).Return(&imds.GetMetadataOutput{
+				Content: io.NopCloser(strings.NewReader("platform")),
+			}, nil).Maybe()
+
+			utils.On("imdsClient", mock.Anything).Return(imdsClient).Maybe()
+
+			eksResourceDetector := newResourceDetector(utils, append([]Option{
+				WithClusterNameEnv("CLUSTER_NAME"),
+				WithDescribeClusterFields(ClusterARN, ClusterVersion, ClusterTags),
+				WithMaxAttributes(table.max),
+			}, table.options...)...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, table.expected...), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimeBudget(t *testing.T) {
+	t.Parallel()
+
+	const total = 10 * time.Second
+
+	start := time.Now()
+
+	// Each step should have a deadline of its share of the total budget
+	deadline := func(fraction float64) any {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			d, ok := ctx.Deadline()
+			if !ok {
+				return false
+			}
+
+			expected := time.Duration(fraction * float64(total))
+
+			return d.Sub(start) > expected-time.Second && d.Sub(start) < expected+time.Second
+		})
+	}
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", deadline(0.1), "tcp", testHost, mock.Anything, time.Duration(0)).Return(conn, nil).Once()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", deadline(0.2), mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Once()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+	eksClient := new(mockEKSClient)
+	eksClient.On("ListClusters", deadline(0.3), mock.Anything, mock.Anything).Return(&eks.ListClustersOutput{
+		Clusters: []string{
+			"other-cluster",
+			"test-cluster",
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", deadline(0.4), &eks.DescribeClusterInput{
+		Name: aws.String("other-cluster"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint: aws.String("https://def456.eu-west-1.eks.amazonaws.com"),
+		},
+	}, nil).Once()
+	eksClient.On("DescribeCluster", deadline(0.4), &eks.DescribeClusterInput{
+		Name: aws.String("test-cluster"),
+	}, mock.Anything).Return(&eks.DescribeClusterOutput{
+		Cluster: &ekstypes.Cluster{
+			Endpoint: aws.String("https://abc123.eu-west-1.eks.amazonaws.com"),
+		},
+	}, nil).Once()
+
+	utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+	eksResourceDetector := newResourceDetector(utils, WithTimeBudget(map[string]float64{
+		StepDial:     0.1,
+		StepSTS:      0.2,
+		StepList:     0.3,
+		StepDescribe: 0.4,
+	}))
+
+	ctx, cancel := context.WithDeadline(t.Context(), start.Add(total))
+	defer cancel()
+
+	r, err := eksResourceDetector.Detect(ctx)
+	require.NoError(t, err)
+
+	v, ok := r.Set().Value(semconv.K8SClusterNameKey)
+	require.True(t, ok)
+	assert.Equal(t, "test-cluster", v.AsString())
+
+	utils.AssertExpectations(t)
+	stsClient.AssertExpectations(t)
+	eksClient.AssertExpectations(t)
+}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name   string
+		dns    string
+		spans  []string
+		branch string
+	}{
+		{
+			name: "eks",
+			dns:  "abc123.eu-west-1.eks.amazonaws.com",
+			spans: []string{
+				"aws.eks.dial",
+				"aws.eks.sts",
+				"aws.eks.Detect",
+			},
+			branch: branchEKS,
+		},
+		{
+			name: "not eks",
+			dns:  "k8s.example.com",
+			spans: []string{
+				"aws.eks.dial",
+				"aws.eks.Detect",
+			},
+			branch: branchNotEKS,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn(table.dns)
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+
+			if table.branch == branchEKS {
+				utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+				stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+					Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+				}, nil).Once()
+
+				utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+			}
+
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameEnv("CLUSTER_NAME"),
+				WithTracer(provider.Tracer("test")),
+			)
+
+			_, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+
+			spans := recorder.Ended()
+
+			names := make([]string, 0, len(spans))
+			for _, span := range spans {
+				names = append(names, span.Name())
+			}
+
+			assert.Equal(t, table.spans, names)
+
+			detect := spans[len(spans)-1]
+			assert.Contains(t, detect.Attributes(), branchKey.String(table.branch))
+
+			for _, span := range spans[:len(spans)-1] {
+				assert.Equal(t, detect.SpanContext().SpanID(), span.Parent().SpanID())
+			}
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedactedAttributes(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		hasher   func([]byte) string
+		expected string
+	}{
+		{
+			name:     "default hasher",
+			expected: "2c5f8bdadc16bc3a",
+		},
+		{
+			name: "custom hasher",
+			hasher: func(b []byte) string {
+				return strings.Repeat("x", len(b))
+			},
+			expected: "xxxxxxxxxxxxx",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+			utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameEnv("CLUSTER_NAME"),
+				WithRedactedAttributes(table.hasher, string(semconv.CloudAccountIDKey)),
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+
+			v, ok := r.Set().Value(semconv.CloudAccountIDKey)
+			require.True(t, ok)
+			assert.Equal(t, table.expected, v.AsString())
+
+			// Other attributes are left alone
+			v, ok = r.Set().Value(semconv.K8SClusterNameKey)
+			require.True(t, ok)
+			assert.Equal(t, "test-cluster", v.AsString())
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInstanceTags(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		enabled  bool
+		expected []attribute.KeyValue
+	}{
+		{
+			name:    "tags enabled",
+			enabled: true,
+			expected: []attribute.KeyValue{
+				attribute.String("aws.ec2.tag.team", "platform"),
+			},
+		},
+		{
+			name: "tags disabled",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn()
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			imdsClient := new(mockIMDSClient)
+			imdsClient.On("GetInstanceIdentityDocument", mock.Anything, mock.Anything, mock.Anything).Return(&imds.GetInstanceIdentityDocumentOutput{
+				InstanceIdentityDocument: imds.InstanceIdentityDocument{
+					AvailabilityZone: "eu-west-1a",
+					Region:           "eu-west-1",
+					InstanceID:       "i-0123456789abcdef0",
+					InstanceType:     "m7i.large",
+					AccountID:        "0123456789012",
+					ImageID:          "ami-0123456789abcdef0",
+				},
+			}, nil).Once()
+
+			if table.enabled {
+				imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+					Path: "tags/instance",
+				}, mock.Anything).Return(&imds.GetMetadataOutput{
+					Content: io.NopCloser(strings.NewReader("Name\nteam")),
+				}, nil).Once()
+				imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+					Path: "tags/instance/team",
+				}, mock.Anything).Return(&imds.GetMetadataOutput{
+					Content: io.NopCloser(strings.NewReader("platform")),
+				}, nil).Once()
+			} else {
+				// The instance metadata returns a 404
+				imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+					Path: "tags/instance",
+				}, mock.Anything).Return(nil, errTest).Once()
+			}
+
+			utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils, WithEC2Fallback(), WithInstanceTags("team", "owner"))
+
+			expected := resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEC2,
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+				semconv.CloudAvailabilityZone("eu-west-1a"),
+				semconv.CloudAccountID("0123456789012"),
+				semconv.HostID("i-0123456789abcdef0"),
+				semconv.HostType("m7i.large"),
+				semconv.HostImageID("ami-0123456789abcdef0"),
+			}, table.expected...)...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, expected, r)
+
+			utils.AssertExpectations(t)
+			imdsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+	conn := newMockTLSConn()
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+	eksResourceDetector := newResourceDetector(utils)
+
+	// Nothing has been cached yet
+	require.NoError(t, eksResourceDetector.Close())
+
+	_, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	_, ok := ConnectionState(eksResourceDetector)
+	require.True(t, ok)
+
+	require.NoError(t, eksResourceDetector.Close())
+	require.NoError(t, eksResourceDetector.Close())
+
+	_, ok = ConnectionState(eksResourceDetector)
+	assert.False(t, ok)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNegativeCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name   string
+		ttl    time.Duration
+		probes int
+	}{
+		{
+			name:   "within ttl",
+			ttl:    time.Hour,
+			probes: 1,
+		},
+		{
+			name:   "expired",
+			ttl:    time.Nanosecond,
+			probes: 2,
+		},
+		{
+			name:   "disabled",
+			probes: 2,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(nil, rest.ErrNotInCluster).Times(table.probes)
+
+			eksResourceDetector := newResourceDetector(utils, WithNegativeCacheTTL(table.ttl))
+
+			for range 2 {
+				r, err := eksResourceDetector.Detect(t.Context())
+				require.NoError(t, err)
+				assert.Equal(t, resource.Empty(), r)
+			}
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNegativeCacheClose(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(nil, rest.ErrNotInCluster).Twice()
+
+	eksResourceDetector := newResourceDetector(utils, WithNegativeCacheTTL(time.Hour))
+
+	_, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	// Forgetting the cached result probes again
+	require.NoError(t, eksResourceDetector.Close())
+
+	_, err = eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	utils.AssertExpectations(t)
+}
+
+func TestClusterNameResolver(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		err      error
+		expected []attribute.KeyValue
+	}{
+		{
+			name: "resolved",
+			expected: []attribute.KeyValue{
+				semconv.K8SClusterName("abc123"),
+			},
+		},
+		{
+			name: "error",
+			err:  errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			// No clusters are listed or described
+			eksClient := new(mockEKSClient)
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameResolver(func(_ context.Context, client APIClient, endpoint string) (string, error) {
+					assert.Same(t, eksClient, client)
+
+					if table.err != nil {
+						return "", table.err
+					}
+
+					name, _, _ := strings.Cut(endpoint, ".")
+
+					return name, nil
+				}),
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+				assert.Nil(t, r)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+					semconv.CloudProviderAWS,
+					semconv.CloudPlatformAWSEKS,
+					semconv.CloudAccountID("0123456789012"),
+					semconv.CloudRegion("eu-west-1"),
+					partitionKey.String("aws"),
+				}, table.expected...)...), r)
+			}
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClusterNameSources(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		env      bool
+		expected []attribute.KeyValue
+	}{
+		{
+			name: "env",
+			env:  true,
+			expected: []attribute.KeyValue{
+				semconv.K8SClusterName("test-cluster"),
+			},
+		},
+		{
+			name: "error",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			// The API source fails so the env source is tried next
+			eksClient := new(mockEKSClient)
+			eksClient.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(nil, errTest).Once()
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			if table.env {
+				utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+			} else {
+				utils.On("lookupEnv", "CLUSTER_NAME").Return("", false).Once()
+			}
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameSources(ClusterNameFromAPI(), ClusterNameFromEnv("CLUSTER_NAME")),
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			if table.expected == nil {
+				require.ErrorIs(t, err, errTest)
+				assert.Nil(t, r)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+					semconv.CloudProviderAWS,
+					semconv.CloudPlatformAWSEKS,
+					semconv.CloudAccountID("0123456789012"),
+					semconv.CloudRegion("eu-west-1"),
+					partitionKey.String("aws"),
+				}, table.expected...)...), r)
+			}
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClusterNameSourceOptions(t *testing.T) {
+	t.Parallel()
+
+	resolver := func(_ context.Context, _ APIClient, _ string) (string, error) {
+		return "resolved-cluster", nil
+	}
+
+	tables := []struct {
+		name     string
+		options  []Option
+		expected string
+	}{
+		{
+			name: "resolver last",
+			options: []Option{
+				WithClusterNameSources(StaticClusterName("static-cluster")),
+				WithClusterNameResolver(resolver),
+			},
+			expected: "resolved-cluster",
+		},
+		{
+			name: "sources last",
+			options: []Option{
+				WithClusterNameResolver(resolver),
+				WithClusterNameSources(ClusterNameFunc(func(_ context.Context, _ APIClient, _ string) (string, error) {
+					return "", nil
+				}), StaticClusterName("static-cluster")),
+			},
+			expected: "static-cluster",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			// No clusters are listed or described
+			eksClient := new(mockEKSClient)
+
+			utils.On("eksClient", mock.Anything).Return(eksClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils, table.options...)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+				semconv.K8SClusterName(table.expected),
+			}...), r)
+
+			utils.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+			eksClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestComputeType(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		nodeName string
+		imds     bool
+		expected []attribute.KeyValue
+	}{
+		{
+			name:     "fargate",
+			nodeName: "fargate-ip-192-168-1-2.eu-west-1.compute.internal",
+			expected: []attribute.KeyValue{
+				computeTypeKey.String("fargate"),
+			},
+		},
+		{
+			name:     "ec2",
+			nodeName: "ip-192-168-1-2.eu-west-1.compute.internal",
+			imds:     true,
+			expected: []attribute.KeyValue{
+				computeTypeKey.String("ec2"),
+				lifecycleKey.String("spot"),
+			},
+		},
+		{
+			name: "no node name",
+			imds: true,
+			expected: []attribute.KeyValue{
+				lifecycleKey.String("spot"),
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Once()
+
+			conn := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+			utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn, nil).Once()
+			utils.On("lookupEnv", NodeNameEnv).Return(table.nodeName, table.nodeName != "").Once()
+			utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Once()
+
+			imdsClient := new(mockIMDSClient)
+
+			// The instance metadata isn't used on Fargate
+			if table.imds {
+				imdsClient.On("GetMetadata", mock.Anything, &imds.GetMetadataInput{
+					Path: "instance-life-cycle",
+				}, mock.Anything).Return(&imds.GetMetadataOutput{
+					Content: io.NopCloser(strings.NewReader("spot")),
+				}, nil).Once()
+
+				utils.On("imdsClient", mock.Anything).Return(imdsClient).Once()
+			}
+
+			stsClient := new(mockSTSClient)
+			stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+			}, nil).Once()
+
+			utils.On("stsClient", mock.Anything).Return(stsClient).Once()
+
+			eksResourceDetector := newResourceDetector(utils,
+				WithClusterNameEnv("CLUSTER_NAME"),
+				WithComputeType(),
+				WithInstanceLifecycle(),
+			)
+
+			r, err := eksResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudPlatformAWSEKS,
+				semconv.CloudAccountID("0123456789012"),
+				semconv.CloudRegion("eu-west-1"),
+				partitionKey.String("aws"),
+				semconv.K8SClusterName("test-cluster"),
+			}, table.expected...)...), r)
+
+			utils.AssertExpectations(t)
+			imdsClient.AssertExpectations(t)
+			stsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDetectAttributes(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("inClusterConfig").Return(&rest.Config{Host: testHost}, nil).Twice()
+
+	conn1 := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+	conn2 := newMockTLSConn("abc123.eu-west-1.eks.amazonaws.com")
+
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn1, nil).Once()
+	utils.On("dial", mock.Anything, "tcp", testHost, mock.Anything, mock.Anything).Return(conn2, nil).Once()
+	utils.On("lookupEnv", "CLUSTER_NAME").Return("test-cluster", true).Twice()
+
+	stsClient := new(mockSTSClient)
+	stsClient.On("GetCallerIdentity", mock.Anything, mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+		Arn: aws.String("arn:aws:iam:eu-west-1:0123456789012:role/test"),
+	}, nil).Twice()
+
+	utils.On("stsClient", mock.Anything).Return(stsClient).Twice()
+
+	eksResourceDetector := newResourceDetector(utils, WithClusterNameEnv("CLUSTER_NAME"))
+
+	r, err := eksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	attributes, err := detectors.DetectAttributes(t.Context(), eksResourceDetector)
+	require.NoError(t, err)
+	assert.Equal(t, r.Attributes(), attributes)
+	assert.Equal(t, []attribute.KeyValue{
+		partitionKey.String("aws"),
+		semconv.CloudAccountID("0123456789012"),
+		semconv.CloudPlatformAWSEKS,
+		semconv.CloudProviderAWS,
+		semconv.CloudRegion("eu-west-1"),
+		semconv.K8SClusterName("test-cluster"),
+	}, attributes)
+
+	utils.AssertExpectations(t)
+	stsClient.AssertExpectations(t)
+}
@@ -0,0 +1,152 @@
+// Package emr provides an OpenTelemetry detector for detecting Amazon EMR
+// cluster instance resources.
+package emr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	jobFlowFile           = "mnt/var/lib/info/job-flow.json"
+	instanceFile          = "mnt/var/lib/info/instance.json"
+	extraInstanceDataFile = "mnt/var/lib/info/extraInstanceData.json"
+)
+
+const (
+	clusterIDKey       = attribute.Key("aws.emr.cluster.id")
+	instanceGroupIDKey = attribute.Key("aws.emr.instance_group.id")
+	instanceRoleKey    = attribute.Key("aws.emr.instance.role")
+)
+
+type instanceGroup struct {
+	InstanceGroupID string `json:"instanceGroupId"`
+	InstanceRole    string `json:"instanceRole"`
+}
+
+// jobFlow is the subset of job-flow.json that describes the cluster.
+type jobFlow struct {
+	JobFlowID      string          `json:"jobFlowId"`
+	InstanceGroups []instanceGroup `json:"instanceGroups"`
+}
+
+// instance is the subset of instance.json that describes this instance.
+type instance struct {
+	InstanceGroupID string `json:"instanceGroupId"`
+}
+
+// extraInstanceData is the subset of extraInstanceData.json that describes
+// this instance.
+type extraInstanceData struct {
+	Region string `json:"region"`
+}
+
+type resourceDetector struct {
+	fsys fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	jf := new(jobFlow)
+
+	ok, err := detector.readJSON(jobFlowFile, jf)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// Not an EMR cluster instance
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+	}
+
+	if jf.JobFlowID != "" {
+		attributes = append(attributes, clusterIDKey.String(jf.JobFlowID))
+	}
+
+	// The job flow lists every instance group, so work out which one this
+	// instance belongs to
+	i := new(instance)
+	if _, err := detector.readJSON(instanceFile, i); err != nil {
+		return nil, err
+	}
+
+	if i.InstanceGroupID != "" {
+		attributes = append(attributes, instanceGroupIDKey.String(i.InstanceGroupID))
+
+		for _, group := range jf.InstanceGroups {
+			if group.InstanceGroupID == i.InstanceGroupID && group.InstanceRole != "" {
+				attributes = append(attributes, instanceRoleKey.String(group.InstanceRole))
+
+				break
+			}
+		}
+	}
+
+	data := new(extraInstanceData)
+	if _, err := detector.readJSON(extraInstanceDataFile, data); err != nil {
+		return nil, err
+	}
+
+	if data.Region != "" {
+		attributes = append(attributes, semconv.CloudRegion(data.Region))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+func (detector *resourceDetector) readJSON(file string, v any) (bool, error) {
+	b, err := fs.ReadFile(detector.fsys, file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error reading %s: %w", file, err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("error decoding %s: %w", file, err)
+	}
+
+	return true, nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read the EMR instance information under
+// /mnt/var/lib/info. It should be rooted at "/". The default is the real OS
+// filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect Amazon EMR
+// cluster instance resources.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		fsys: os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
@@ -0,0 +1,124 @@
+package emr
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const testJobFlow = `{
+  "jobFlowId": "j-2AXXXXXXGAPLF",
+  "jobFlowCreationInstant": 1429046932628,
+  "instanceCount": 2,
+  "masterInstanceId": "i-08dea4f4",
+  "masterPrivateDnsName": "localhost",
+  "masterInstanceType": "m1.medium",
+  "slaveInstanceType": "m1.xlarge",
+  "hadoopVersion": "2.4.0",
+  "instanceGroups": [
+    {
+      "instanceGroupId": "ig-16NXM94TY33LB",
+      "instanceGroupName": "CORE",
+      "instanceRole": "Core",
+      "marketType": "OnDemand",
+      "instanceType": "m1.xlarge",
+      "requestedInstanceCount": 1
+    },
+    {
+      "instanceGroupId": "ig-2XQ29JGCTKLBL",
+      "instanceGroupName": "MASTER",
+      "instanceRole": "Master",
+      "marketType": "OnDemand",
+      "instanceType": "m1.medium",
+      "requestedInstanceCount": 1
+    }
+  ]
+}`
+
+func TestEMR(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected *resource.Resource
+	}{
+		{
+			name: "master",
+			fsys: fstest.MapFS{
+				"mnt/var/lib/info/job-flow.json": &fstest.MapFile{Data: []byte(testJobFlow)},
+				"mnt/var/lib/info/instance.json": &fstest.MapFile{
+					Data: []byte(`{"isMaster": true, "instanceGroupId": "ig-2XQ29JGCTKLBL"}`),
+				},
+				"mnt/var/lib/info/extraInstanceData.json": &fstest.MapFile{
+					Data: []byte(`{"region": "eu-west-1", "numCandidates": 1}`),
+				},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				clusterIDKey.String("j-2AXXXXXXGAPLF"),
+				instanceGroupIDKey.String("ig-2XQ29JGCTKLBL"),
+				instanceRoleKey.String("Master"),
+				semconv.CloudRegion("eu-west-1"),
+			),
+		},
+		{
+			name: "core",
+			fsys: fstest.MapFS{
+				"mnt/var/lib/info/job-flow.json": &fstest.MapFile{Data: []byte(testJobFlow)},
+				"mnt/var/lib/info/instance.json": &fstest.MapFile{
+					Data: []byte(`{"isMaster": false, "instanceGroupId": "ig-16NXM94TY33LB"}`),
+				},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				clusterIDKey.String("j-2AXXXXXXGAPLF"),
+				instanceGroupIDKey.String("ig-16NXM94TY33LB"),
+				instanceRoleKey.String("Core"),
+			),
+		},
+		{
+			name: "job flow only",
+			fsys: fstest.MapFS{
+				"mnt/var/lib/info/job-flow.json": &fstest.MapFile{Data: []byte(testJobFlow)},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.CloudProviderAWS,
+				clusterIDKey.String("j-2AXXXXXXGAPLF"),
+			),
+		},
+		{
+			name:     "not emr",
+			fsys:     fstest.MapFS{},
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			emrResourceDetector := NewResourceDetector(WithFS(table.fsys))
+
+			r, err := emrResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	t.Parallel()
+
+	emrResourceDetector := NewResourceDetector(WithFS(fstest.MapFS{
+		"mnt/var/lib/info/job-flow.json": &fstest.MapFile{Data: []byte("{")},
+	}))
+
+	r, err := emrResourceDetector.Detect(t.Context())
+	require.Error(t, err)
+	assert.Nil(t, r)
+}
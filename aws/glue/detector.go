@@ -0,0 +1,80 @@
+// Package glue provides an OpenTelemetry detector for detecting AWS Glue job
+// resources.
+package glue
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	jobNameEnv = "GLUE_JOB_NAME"
+	runIDEnv   = "GLUE_RUN_ID"
+	regionEnv  = "AWS_REGION"
+)
+
+const (
+	jobNameKey = attribute.Key("aws.glue.job.name")
+	runIDKey   = attribute.Key("aws.glue.job.run_id")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type glueDetectorUtils struct{}
+
+func (utils *glueDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	jobName, _ := detector.utils.lookupEnv(jobNameEnv)
+	if jobName == "" {
+		// Not an AWS Glue job
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		jobNameKey.String(jobName),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			runIDEnv,
+			runIDKey.String,
+		},
+		{
+			regionEnv,
+			semconv.CloudRegion,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS Glue
+// job resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(glueDetectorUtils),
+	}
+}
@@ -0,0 +1,59 @@
+package glue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestGlue(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", jobNameEnv).Return("nightly-etl", true).Once()
+	utils.On("lookupEnv", runIDEnv).Return("jr_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", true).Once()
+	utils.On("lookupEnv", regionEnv).Return("eu-west-1", true).Once()
+
+	glueResourceDetector := resourceDetector{utils: utils}
+
+	r, err := glueResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		jobNameKey.String("nightly-etl"),
+		runIDKey.String("jr_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"),
+		semconv.CloudRegion("eu-west-1"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotGlue(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", jobNameEnv).Return("", false).Once()
+
+	glueResourceDetector := resourceDetector{utils: utils}
+
+	r, err := glueResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
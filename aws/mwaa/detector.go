@@ -0,0 +1,87 @@
+// Package mwaa provides an OpenTelemetry detector for detecting Amazon Managed
+// Workflows for Apache Airflow (MWAA) resources.
+package mwaa
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	componentEnv = "MWAA_AIRFLOW_COMPONENT"
+	envNameEnv   = "AIRFLOW_ENV_NAME"
+	regionEnv    = "AWS_REGION"
+)
+
+const (
+	environmentNameKey = attribute.Key("aws.mwaa.environment.name")
+	componentKey       = attribute.Key("aws.mwaa.component")
+)
+
+// There's no semantic convention value for MWAA.
+//
+//nolint:gochecknoglobals
+var platformAWSMWAA = semconv.CloudPlatformKey.String("aws_mwaa")
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type mwaaDetectorUtils struct{}
+
+func (utils *mwaaDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	// One of "scheduler", "worker", or "webserver"
+	component, _ := detector.utils.lookupEnv(componentEnv)
+	if component == "" {
+		// Not an MWAA environment
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		platformAWSMWAA,
+		componentKey.String(component),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			envNameEnv,
+			environmentNameKey.String,
+		},
+		{
+			regionEnv,
+			semconv.CloudRegion,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Amazon
+// MWAA resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(mwaaDetectorUtils),
+	}
+}
@@ -0,0 +1,60 @@
+package mwaa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestMWAA(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", componentEnv).Return("worker", true).Once()
+	utils.On("lookupEnv", envNameEnv).Return("data-pipelines", true).Once()
+	utils.On("lookupEnv", regionEnv).Return("eu-west-1", true).Once()
+
+	mwaaResourceDetector := resourceDetector{utils: utils}
+
+	r, err := mwaaResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		platformAWSMWAA,
+		componentKey.String("worker"),
+		environmentNameKey.String("data-pipelines"),
+		semconv.CloudRegion("eu-west-1"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotMWAA(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", componentEnv).Return("", false).Once()
+
+	mwaaResourceDetector := resourceDetector{utils: utils}
+
+	r, err := mwaaResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,102 @@
+// Package sagemaker provides an OpenTelemetry detector for detecting Amazon
+// SageMaker training job resources.
+package sagemaker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	trainingEnvEnv     = "SM_TRAINING_ENV"
+	trainingJobNameEnv = "TRAINING_JOB_NAME"
+	regionEnv          = "SAGEMAKER_REGION"
+)
+
+const trainingJobNameKey = attribute.Key("aws.sagemaker.training_job.name")
+
+// There's no semantic convention value for SageMaker.
+//
+//nolint:gochecknoglobals
+var platformAWSSageMaker = semconv.CloudPlatformKey.String("aws_sagemaker")
+
+// trainingEnv is the subset of the JSON in the SM_TRAINING_ENV environment
+// variable.
+type trainingEnv struct {
+	JobName string `json:"job_name"` //nolint:tagliatelle
+}
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type sageMakerDetectorUtils struct{}
+
+func (utils *sageMakerDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) jobName() (string, bool, error) {
+	if v, _ := detector.utils.lookupEnv(trainingJobNameEnv); v != "" {
+		return v, true, nil
+	}
+
+	v, _ := detector.utils.lookupEnv(trainingEnvEnv)
+	if v == "" {
+		return "", false, nil
+	}
+
+	env := new(trainingEnv)
+	if err := json.Unmarshal([]byte(v), env); err != nil {
+		return "", false, fmt.Errorf("error decoding %s: %w", trainingEnvEnv, err)
+	}
+
+	return env.JobName, true, nil
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	jobName, ok, err := detector.jobName()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// Not a SageMaker training job
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		platformAWSSageMaker,
+	}
+
+	if jobName != "" {
+		attributes = append(attributes, trainingJobNameKey.String(jobName))
+	}
+
+	if v, _ := detector.utils.lookupEnv(regionEnv); v != "" {
+		attributes = append(attributes, semconv.CloudRegion(v))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Amazon
+// SageMaker training job resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(sageMakerDetectorUtils),
+	}
+}
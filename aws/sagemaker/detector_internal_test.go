@@ -0,0 +1,97 @@
+package sagemaker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestTrainingJobNameEnv(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", trainingJobNameEnv).Return("xgboost-2025-01-01-00-00-00-000", true).Once()
+	utils.On("lookupEnv", regionEnv).Return("eu-west-1", true).Once()
+
+	sageMakerResourceDetector := resourceDetector{utils: utils}
+
+	r, err := sageMakerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		platformAWSSageMaker,
+		trainingJobNameKey.String("xgboost-2025-01-01-00-00-00-000"),
+		semconv.CloudRegion("eu-west-1"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+	utils.AssertNotCalled(t, "lookupEnv", trainingEnvEnv)
+}
+
+func TestTrainingEnv(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", trainingJobNameEnv).Return("", false).Once()
+	utils.On("lookupEnv", trainingEnvEnv).Return(`{"current_host":"algo-1","hosts":["algo-1"],"job_name":"pytorch-training-2025-01-01-00-00-00-000","num_gpus":1}`, true).Once()
+	utils.On("lookupEnv", regionEnv).Return("", false).Once()
+
+	sageMakerResourceDetector := resourceDetector{utils: utils}
+
+	r, err := sageMakerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		platformAWSSageMaker,
+		trainingJobNameKey.String("pytorch-training-2025-01-01-00-00-00-000"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestTrainingEnvInvalid(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", trainingJobNameEnv).Return("", false).Once()
+	utils.On("lookupEnv", trainingEnvEnv).Return("{", true).Once()
+
+	sageMakerResourceDetector := resourceDetector{utils: utils}
+
+	r, err := sageMakerResourceDetector.Detect(t.Context())
+	require.Error(t, err)
+	assert.Nil(t, r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotSageMaker(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", trainingJobNameEnv).Return("", false).Once()
+	utils.On("lookupEnv", trainingEnvEnv).Return("", false).Once()
+
+	sageMakerResourceDetector := resourceDetector{utils: utils}
+
+	r, err := sageMakerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,113 @@
+// Package stepfunctions provides an OpenTelemetry detector for detecting AWS
+// Step Functions execution resources.
+package stepfunctions
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// Step Functions doesn't set any environment variables itself so these should
+// be set from the context object when the task is invoked, for example by
+// passing "$$.Execution.Id" and "$$.StateMachine.Id" as environment overrides
+// to an ECS or Batch task, or as environment variables of a Lambda function
+// dedicated to the state machine.
+const (
+	// ExecutionARNEnv is the environment variable for the execution ARN.
+	ExecutionARNEnv = "AWS_STEP_FUNCTIONS_EXECUTION_ARN"
+	// StateMachineARNEnv is the environment variable for the state machine
+	// ARN. It is only needed if the execution ARN isn't available.
+	StateMachineARNEnv = "AWS_STEP_FUNCTIONS_STATE_MACHINE_ARN"
+)
+
+const (
+	stateMachineARNKey  = attribute.Key("aws.step_functions.state_machine.arn")
+	stateMachineNameKey = attribute.Key("aws.step_functions.state_machine.name")
+	executionARNKey     = attribute.Key("aws.step_functions.execution.arn")
+	executionNameKey    = attribute.Key("aws.step_functions.execution.name")
+)
+
+const (
+	resourceTypeField = 5
+	stateMachineField = 6
+	executionField    = 7
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type stepFunctionsDetectorUtils struct{}
+
+func (utils *stepFunctionsDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	executionARN, _ := detector.utils.lookupEnv(ExecutionARNEnv)
+	stateMachineARN, _ := detector.utils.lookupEnv(StateMachineARNEnv)
+
+	if executionARN == "" && stateMachineARN == "" {
+		// Not invoked by Step Functions
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+	}
+
+	if executionARN != "" {
+		attributes = append(attributes, executionARNKey.String(executionARN))
+
+		// Either arn:aws:states:<region>:<account>:execution:<state machine>:<name>
+		// or arn:aws:states:<region>:<account>:express:<state machine>:<name>:<id>
+		fields := strings.Split(executionARN, ":")
+		if len(fields) > executionField && isExecution(fields[resourceTypeField]) {
+			attributes = append(attributes, executionNameKey.String(fields[executionField]))
+
+			if stateMachineARN == "" {
+				fields[resourceTypeField] = "stateMachine"
+				stateMachineARN = strings.Join(fields[:executionField], ":")
+			}
+		}
+	}
+
+	if stateMachineARN != "" {
+		attributes = append(attributes, stateMachineARNKey.String(stateMachineARN))
+
+		// arn:aws:states:<region>:<account>:stateMachine:<name>, optionally
+		// followed by a version or alias
+		fields := strings.Split(stateMachineARN, ":")
+		if len(fields) > stateMachineField && fields[resourceTypeField] == "stateMachine" {
+			attributes = append(attributes, stateMachineNameKey.String(fields[stateMachineField]))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// isExecution reports whether the ARN resource type is for a standard or
+// express workflow execution.
+func isExecution(resourceType string) bool {
+	return resourceType == "execution" || resourceType == "express"
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect AWS Step
+// Functions execution resources using the environment variables
+// [ExecutionARNEnv] and [StateMachineARNEnv].
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(stepFunctionsDetectorUtils),
+	}
+}
@@ -0,0 +1,115 @@
+package stepfunctions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestStepFunctions(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name                          string
+		executionARN, stateMachineARN string
+		expected                      []attribute.KeyValue
+	}{
+		{
+			name:         "standard execution",
+			executionARN: "arn:aws:states:eu-west-1:0123456789012:execution:my-state-machine:my-execution",
+			expected: []attribute.KeyValue{
+				executionARNKey.String("arn:aws:states:eu-west-1:0123456789012:execution:my-state-machine:my-execution"),
+				executionNameKey.String("my-execution"),
+				stateMachineARNKey.String("arn:aws:states:eu-west-1:0123456789012:stateMachine:my-state-machine"),
+				stateMachineNameKey.String("my-state-machine"),
+			},
+		},
+		{
+			name:         "express execution",
+			executionARN: "arn:aws:states:eu-west-1:0123456789012:express:my-state-machine:my-execution:b1e6661e-e4f2-4156-9ab9-82a19EXAMPLE",
+			expected: []attribute.KeyValue{
+				executionARNKey.String("arn:aws:states:eu-west-1:0123456789012:express:my-state-machine:my-execution:b1e6661e-e4f2-4156-9ab9-82a19EXAMPLE"),
+				executionNameKey.String("my-execution"),
+				stateMachineARNKey.String("arn:aws:states:eu-west-1:0123456789012:stateMachine:my-state-machine"),
+				stateMachineNameKey.String("my-state-machine"),
+			},
+		},
+		{
+			name:            "both",
+			executionARN:    "arn:aws:states:eu-west-1:0123456789012:execution:my-state-machine:my-execution",
+			stateMachineARN: "arn:aws:states:eu-west-1:0123456789012:stateMachine:my-state-machine:prod",
+			expected: []attribute.KeyValue{
+				executionARNKey.String("arn:aws:states:eu-west-1:0123456789012:execution:my-state-machine:my-execution"),
+				executionNameKey.String("my-execution"),
+				stateMachineARNKey.String("arn:aws:states:eu-west-1:0123456789012:stateMachine:my-state-machine:prod"),
+				stateMachineNameKey.String("my-state-machine"),
+			},
+		},
+		{
+			name:            "state machine only",
+			stateMachineARN: "arn:aws:states:eu-west-1:0123456789012:stateMachine:my-state-machine",
+			expected: []attribute.KeyValue{
+				stateMachineARNKey.String("arn:aws:states:eu-west-1:0123456789012:stateMachine:my-state-machine"),
+				stateMachineNameKey.String("my-state-machine"),
+			},
+		},
+		{
+			name:         "malformed",
+			executionARN: "my-execution",
+			expected: []attribute.KeyValue{
+				executionARNKey.String("my-execution"),
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", ExecutionARNEnv).Return(table.executionARN, table.executionARN != "").Once()
+			utils.On("lookupEnv", StateMachineARNEnv).Return(table.stateMachineARN, table.stateMachineARN != "").Once()
+
+			stepFunctionsResourceDetector := resourceDetector{utils: utils}
+
+			r, err := stepFunctionsResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				semconv.CloudProviderAWS,
+			}, table.expected...)...), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotStepFunctions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", ExecutionARNEnv).Return("", false).Once()
+	utils.On("lookupEnv", StateMachineARNEnv).Return("", false).Once()
+
+	stepFunctionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := stepFunctionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
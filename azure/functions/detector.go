@@ -0,0 +1,88 @@
+// Package functions provides an OpenTelemetry detector for detecting Azure
+// Functions resources.
+package functions
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	siteNameEnv         = "WEBSITE_SITE_NAME"
+	extensionVersionEnv = "FUNCTIONS_EXTENSION_VERSION"
+	workerRuntimeEnv    = "FUNCTIONS_WORKER_RUNTIME"
+	regionNameEnv       = "REGION_NAME"
+)
+
+const (
+	extensionVersionKey = attribute.Key("azure.functions.extension_version")
+	workerRuntimeKey    = attribute.Key("azure.functions.worker_runtime")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type functionsDetectorUtils struct{}
+
+func (utils *functionsDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	extensionVersion, _ := detector.utils.lookupEnv(extensionVersionEnv)
+	if extensionVersion == "" {
+		// Not an Azure Function
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderAzure,
+		semconv.CloudPlatformAzureFunctions,
+		extensionVersionKey.String(extensionVersion),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			// The function app name
+			siteNameEnv,
+			semconv.FaaSName,
+		},
+		{
+			// Such as "dotnet-isolated", "node", or "python"
+			workerRuntimeEnv,
+			workerRuntimeKey.String,
+		},
+		{
+			regionNameEnv,
+			semconv.CloudRegion,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Azure
+// Functions resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(functionsDetectorUtils),
+	}
+}
@@ -0,0 +1,62 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestFunctions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", extensionVersionEnv).Return("~4", true).Once()
+	utils.On("lookupEnv", siteNameEnv).Return("my-function-app", true).Once()
+	utils.On("lookupEnv", workerRuntimeEnv).Return("dotnet-isolated", true).Once()
+	utils.On("lookupEnv", regionNameEnv).Return("UK South", true).Once()
+
+	functionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := functionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderAzure,
+		semconv.CloudPlatformAzureFunctions,
+		extensionVersionKey.String("~4"),
+		semconv.FaaSName("my-function-app"),
+		workerRuntimeKey.String("dotnet-isolated"),
+		semconv.CloudRegion("UK South"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotFunctions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", extensionVersionEnv).Return("", false).Once()
+
+	functionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := functionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
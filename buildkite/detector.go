@@ -0,0 +1,84 @@
+// Package buildkite provides an OpenTelemetry detector for detecting Buildkite
+// agent resources.
+package buildkite
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	buildIDEnv      = "BUILDKITE_BUILD_ID"
+	jobIDEnv        = "BUILDKITE_JOB_ID"
+	pipelineSlugEnv = "BUILDKITE_PIPELINE_SLUG"
+	commitEnv       = "BUILDKITE_COMMIT"
+	agentNameEnv    = "BUILDKITE_AGENT_NAME"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type buildkiteDetectorUtils struct{}
+
+func (utils *buildkiteDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	buildID, _ := detector.utils.lookupEnv(buildIDEnv)
+	if buildID == "" {
+		// Not a Buildkite build
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineRunID(buildID),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			jobIDEnv,
+			semconv.CICDPipelineTaskRunID,
+		},
+		{
+			pipelineSlugEnv,
+			semconv.CICDPipelineName,
+		},
+		{
+			commitEnv,
+			semconv.VCSRefHeadRevision,
+		},
+		{
+			agentNameEnv,
+			semconv.CICDWorkerName,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Buildkite
+// agent resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(buildkiteDetectorUtils),
+	}
+}
@@ -0,0 +1,62 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestBuildkite(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("f62a1b4d-10f9-4790-bc1c-e2c3a0c80983", true).Once()
+	utils.On("lookupEnv", jobIDEnv).Return("e44f9784-e20e-4b93-a21d-f41fd5869db9", true).Once()
+	utils.On("lookupEnv", pipelineSlugEnv).Return("my-pipeline", true).Once()
+	utils.On("lookupEnv", commitEnv).Return("ffac537e6cbbf934b08745a378932722df287a53", true).Once()
+	utils.On("lookupEnv", agentNameEnv).Return("my-agent-1", true).Once()
+
+	buildkiteResourceDetector := resourceDetector{utils: utils}
+
+	r, err := buildkiteResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("f62a1b4d-10f9-4790-bc1c-e2c3a0c80983"),
+		semconv.CICDPipelineTaskRunID("e44f9784-e20e-4b93-a21d-f41fd5869db9"),
+		semconv.CICDPipelineName("my-pipeline"),
+		semconv.VCSRefHeadRevision("ffac537e6cbbf934b08745a378932722df287a53"),
+		semconv.CICDWorkerName("my-agent-1"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotBuildkite(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("", false).Once()
+
+	buildkiteResourceDetector := resourceDetector{utils: utils}
+
+	r, err := buildkiteResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
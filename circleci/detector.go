@@ -0,0 +1,90 @@
+// Package circleci provides an OpenTelemetry detector for detecting CircleCI
+// job resources.
+package circleci
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	buildNumEnv        = "CIRCLE_BUILD_NUM"
+	jobEnv             = "CIRCLE_JOB"
+	projectUsernameEnv = "CIRCLE_PROJECT_USERNAME"
+	projectRepoNameEnv = "CIRCLE_PROJECT_REPONAME"
+	sha1Env            = "CIRCLE_SHA1"
+	workflowIDEnv      = "CIRCLE_WORKFLOW_ID"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type circleCIDetectorUtils struct{}
+
+func (utils *circleCIDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	buildNum, _ := detector.utils.lookupEnv(buildNumEnv)
+	if buildNum == "" {
+		// Not a CircleCI job
+		return resource.Empty(), nil
+	}
+
+	// Each job is a task within a workflow
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineTaskRunID(buildNum),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			jobEnv,
+			semconv.CICDPipelineTaskName,
+		},
+		{
+			workflowIDEnv,
+			semconv.CICDPipelineRunID,
+		},
+		{
+			projectUsernameEnv,
+			semconv.VCSOwnerName,
+		},
+		{
+			projectRepoNameEnv,
+			semconv.VCSRepositoryName,
+		},
+		{
+			sha1Env,
+			semconv.VCSRefHeadRevision,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect CircleCI
+// job resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(circleCIDetectorUtils),
+	}
+}
@@ -0,0 +1,64 @@
+package circleci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestCircleCI(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildNumEnv).Return("1234", true).Once()
+	utils.On("lookupEnv", jobEnv).Return("build", true).Once()
+	utils.On("lookupEnv", workflowIDEnv).Return("6f1f7ea2-2a5c-4c0b-9a3e-3b3c2e6a1d2f", true).Once()
+	utils.On("lookupEnv", projectUsernameEnv).Return("bodgit", true).Once()
+	utils.On("lookupEnv", projectRepoNameEnv).Return("detectors", true).Once()
+	utils.On("lookupEnv", sha1Env).Return("ffac537e6cbbf934b08745a378932722df287a53", true).Once()
+
+	circleCIResourceDetector := resourceDetector{utils: utils}
+
+	r, err := circleCIResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineTaskRunID("1234"),
+		semconv.CICDPipelineTaskName("build"),
+		semconv.CICDPipelineRunID("6f1f7ea2-2a5c-4c0b-9a3e-3b3c2e6a1d2f"),
+		semconv.VCSOwnerName("bodgit"),
+		semconv.VCSRepositoryName("detectors"),
+		semconv.VCSRefHeadRevision("ffac537e6cbbf934b08745a378932722df287a53"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotCircleCI(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildNumEnv).Return("", false).Once()
+
+	circleCIResourceDetector := resourceDetector{utils: utils}
+
+	r, err := circleCIResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
@@ -5,6 +5,7 @@ package container
 import (
 	"context"
 	"os"
+	"regexp"
 
 	"github.com/bodgit/nri-plugin-runtime/pkg/runtime"
 	"go.opentelemetry.io/otel/attribute"
@@ -23,31 +24,42 @@ func (utils *containerDetectorUtils) lookupEnv(key string) (string, bool) {
 }
 
 type resourceDetector struct {
-	utils detectorUtils
+	utils     detectorUtils
+	validator func(string) bool
+	envPrefix string
+	faas      bool
 }
 
 func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
-	attributes := make([]attribute.KeyValue, 0, 3)
+	attributes := make([]attribute.KeyValue, 0, 4)
 
 	for _, s := range []struct {
-		env string
-		fn  func(string) attribute.KeyValue
+		env   string
+		fn    func(string) attribute.KeyValue
+		valid func(string) bool
 	}{
 		{
 			runtime.ContainerIDEnv,
 			semconv.ContainerID,
+			detector.validator,
 		},
 		{
 			runtime.ContainerRuntimeNameEnv,
 			semconv.ContainerRuntimeName,
+			nil,
 		},
 		{
 			runtime.ContainerRuntimeVersionEnv,
 			semconv.ContainerRuntimeVersion,
+			nil,
 		},
 	} {
-		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+		if v, _ := detector.utils.lookupEnv(detector.envPrefix + s.env); v != "" && (s.valid == nil || s.valid(v)) {
 			attributes = append(attributes, s.fn(v))
+
+			if detector.faas && s.env == runtime.ContainerIDEnv {
+				attributes = append(attributes, semconv.FaaSInstance(v))
+			}
 		}
 	}
 
@@ -60,10 +72,62 @@ func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource,
 
 var _ resource.Detector = new(resourceDetector)
 
+var containerIDRegexp = regexp.MustCompile(`^[0-9a-f]{32,128}$`)
+
+// HexContainerIDValidator is a validator for use with
+// [WithContainerIDValidator]. It accepts lowercase hexadecimal IDs between 32
+// and 128 characters long, which covers the full-length IDs used by the common
+// runtimes while rejecting truncated or malformed IDs.
+func HexContainerIDValidator(id string) bool {
+	return containerIDRegexp.MatchString(id)
+}
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithContainerIDValidator sets the function used to validate the container
+// ID. If the ID fails validation then the container ID attribute is not
+// emitted. The default is to not validate the container ID.
+func WithContainerIDValidator(fn func(string) bool) Option {
+	return func(detector *resourceDetector) {
+		detector.validator = fn
+	}
+}
+
+// WithEnvPrefix sets a prefix that is prepended to the names of the
+// environment variables read by the detector. This allows multiple runtime
+// integrations to set their own namespaced variables. The default is no
+// prefix.
+func WithEnvPrefix(prefix string) Option {
+	return func(detector *resourceDetector) {
+		detector.envPrefix = prefix
+	}
+}
+
+// WithFaaSInstance additionally emits the container ID as the FaaS instance,
+// for containers running a FaaS-like workload. The default is to not emit the
+// FaaS instance.
+func WithFaaSInstance() Option {
+	return func(detector *resourceDetector) {
+		detector.faas = true
+	}
+}
+
+func newResourceDetector(utils detectorUtils, options ...Option) *resourceDetector {
+	detector := &resourceDetector{
+		utils: utils,
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
+
 // NewResourceDetector returns a [resource.Detector] that will detect container
 // resources.
-func NewResourceDetector() resource.Detector {
-	return &resourceDetector{
-		utils: new(containerDetectorUtils),
-	}
+func NewResourceDetector(options ...Option) resource.Detector {
+	return newResourceDetector(new(containerDetectorUtils), options...)
 }
@@ -12,6 +12,8 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
 )
 
+const testContainerID = "3f4e2a1b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f"
+
 type mockDetectorUtils struct {
 	mock.Mock
 }
@@ -30,7 +32,7 @@ func TestContainer(t *testing.T) {
 	utils.On("lookupEnv", runtime.ContainerRuntimeNameEnv).Return("containerd", true).Once()
 	utils.On("lookupEnv", runtime.ContainerRuntimeVersionEnv).Return("2.0.0", true).Once()
 
-	containerResourceDetector := resourceDetector{utils: utils}
+	containerResourceDetector := newResourceDetector(utils)
 
 	r, err := containerResourceDetector.Detect(t.Context())
 	require.NoError(t, err)
@@ -49,7 +51,7 @@ func TestNoPlugin(t *testing.T) {
 	utils := new(mockDetectorUtils)
 	utils.On("lookupEnv", mock.Anything).Return("", false)
 
-	containerResourceDetector := resourceDetector{utils: utils}
+	containerResourceDetector := newResourceDetector(utils)
 
 	r, err := containerResourceDetector.Detect(t.Context())
 	require.NoError(t, err)
@@ -57,3 +59,156 @@ func TestNoPlugin(t *testing.T) {
 
 	utils.AssertExpectations(t)
 }
+
+func TestEnvPrefix(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", "CRIO_"+runtime.ContainerIDEnv).Return(testContainerID, true).Once()
+	utils.On("lookupEnv", "CRIO_"+runtime.ContainerRuntimeNameEnv).Return("cri-o", true).Once()
+	utils.On("lookupEnv", "CRIO_"+runtime.ContainerRuntimeVersionEnv).Return("", false).Once()
+
+	containerResourceDetector := newResourceDetector(utils, WithEnvPrefix("CRIO_"))
+
+	r, err := containerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.ContainerID(testContainerID),
+		semconv.ContainerRuntimeName("cri-o"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+	utils.AssertNotCalled(t, "lookupEnv", runtime.ContainerIDEnv)
+}
+
+func TestFaaSInstance(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		id       string
+		options  []Option
+		expected []attribute.KeyValue
+	}{
+		{
+			name: "default",
+			id:   testContainerID,
+			expected: []attribute.KeyValue{
+				semconv.ContainerID(testContainerID),
+				semconv.ContainerRuntimeName("containerd"),
+			},
+		},
+		{
+			name: "enabled",
+			id:   testContainerID,
+			options: []Option{
+				WithFaaSInstance(),
+			},
+			expected: []attribute.KeyValue{
+				semconv.ContainerID(testContainerID),
+				semconv.FaaSInstance(testContainerID),
+				semconv.ContainerRuntimeName("containerd"),
+			},
+		},
+		{
+			name: "no container id",
+			options: []Option{
+				WithFaaSInstance(),
+			},
+			expected: []attribute.KeyValue{
+				semconv.ContainerRuntimeName("containerd"),
+			},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", runtime.ContainerIDEnv).Return(table.id, table.id != "").Once()
+			utils.On("lookupEnv", runtime.ContainerRuntimeNameEnv).Return("containerd", true).Once()
+			utils.On("lookupEnv", runtime.ContainerRuntimeVersionEnv).Return("", false).Once()
+
+			containerResourceDetector := newResourceDetector(utils, table.options...)
+
+			r, err := containerResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, table.expected...), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContainerIDValidator(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		id        string
+		validator func(string) bool
+		valid     bool
+	}{
+		{
+			"valid",
+			testContainerID,
+			HexContainerIDValidator,
+			true,
+		},
+		{
+			"truncated",
+			testContainerID[:12],
+			HexContainerIDValidator,
+			false,
+		},
+		{
+			"malformed",
+			"not-a-container-id",
+			HexContainerIDValidator,
+			false,
+		},
+		{
+			"none",
+			"not-a-container-id",
+			nil,
+			true,
+		},
+		{
+			"custom",
+			"abc123",
+			func(id string) bool {
+				return id != ""
+			},
+			true,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", runtime.ContainerIDEnv).Return(table.id, true).Once()
+			utils.On("lookupEnv", runtime.ContainerRuntimeNameEnv).Return("containerd", true).Once()
+			utils.On("lookupEnv", runtime.ContainerRuntimeVersionEnv).Return("2.0.0", true).Once()
+
+			containerResourceDetector := newResourceDetector(utils, WithContainerIDValidator(table.validator))
+
+			attributes := []attribute.KeyValue{
+				semconv.ContainerRuntimeName("containerd"),
+				semconv.ContainerRuntimeVersion("2.0.0"),
+			}
+
+			if table.valid {
+				attributes = append(attributes, semconv.ContainerID(table.id))
+			}
+
+			r, err := containerResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, attributes...), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
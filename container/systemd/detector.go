@@ -0,0 +1,122 @@
+// Package systemd provides an OpenTelemetry detector for detecting
+// systemd-nspawn container resources.
+package systemd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	containerEnv  = "container"
+	containerFile = "run/systemd/container"
+
+	nspawn = "systemd-nspawn"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+	hostname() (string, error)
+}
+
+type systemdDetectorUtils struct{}
+
+func (utils *systemdDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (utils *systemdDetectorUtils) hostname() (string, error) {
+	return os.Hostname()
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+	fsys  fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	ok, err := detector.isNspawn()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// Not a systemd-nspawn container
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.ContainerRuntimeName(nspawn),
+	}
+
+	// The hostname defaults to the machine name, as shown by machinectl(1)
+	name, err := detector.utils.hostname()
+	if err != nil {
+		return nil, fmt.Errorf("error getting hostname: %w", err)
+	}
+
+	if name != "" {
+		attributes = append(attributes, semconv.ContainerID(name))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+func (detector *resourceDetector) isNspawn() (bool, error) {
+	// Only PID 1 is guaranteed to have this set
+	if v, _ := detector.utils.lookupEnv(containerEnv); v != "" {
+		return v == nspawn, nil
+	}
+
+	b, err := fs.ReadFile(detector.fsys, containerFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error reading %s: %w", containerFile, err)
+	}
+
+	return string(bytes.TrimSpace(b)) == nspawn, nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read /run/systemd/container. It should be
+// rooted at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+func newResourceDetector(utils detectorUtils, options ...Option) *resourceDetector {
+	detector := &resourceDetector{
+		utils: utils,
+		fsys:  os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// systemd-nspawn container resources.
+func NewResourceDetector(options ...Option) resource.Detector {
+	return newResourceDetector(new(systemdDetectorUtils), options...)
+}
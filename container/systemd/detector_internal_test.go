@@ -0,0 +1,122 @@
+package systemd
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+var errTest = errors.New("test")
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func (utils *mockDetectorUtils) hostname() (string, error) {
+	args := utils.Called()
+
+	return args.String(0), args.Error(1)
+}
+
+func TestSystemd(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		env      string
+		fsys     fstest.MapFS
+		hostname bool
+		expected *resource.Resource
+	}{
+		{
+			name:     "env",
+			env:      "systemd-nspawn",
+			fsys:     fstest.MapFS{},
+			hostname: true,
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.ContainerRuntimeName("systemd-nspawn"),
+				semconv.ContainerID("debian-tree"),
+			),
+		},
+		{
+			name: "file",
+			fsys: fstest.MapFS{
+				"run/systemd/container": &fstest.MapFile{Data: []byte("systemd-nspawn\n")},
+			},
+			hostname: true,
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.ContainerRuntimeName("systemd-nspawn"),
+				semconv.ContainerID("debian-tree"),
+			),
+		},
+		{
+			name: "other container",
+			env:  "docker",
+			fsys: fstest.MapFS{
+				"run/systemd/container": &fstest.MapFile{Data: []byte("systemd-nspawn\n")},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name: "other container file",
+			fsys: fstest.MapFS{
+				"run/systemd/container": &fstest.MapFile{Data: []byte("lxc\n")},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name:     "not a container",
+			fsys:     fstest.MapFS{},
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", containerEnv).Return(table.env, table.env != "").Once()
+
+			if table.hostname {
+				utils.On("hostname").Return("debian-tree", nil).Once()
+			}
+
+			systemdResourceDetector := newResourceDetector(utils, WithFS(table.fsys))
+
+			r, err := systemdResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHostnameError(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", containerEnv).Return("systemd-nspawn", true).Once()
+	utils.On("hostname").Return("", errTest).Once()
+
+	systemdResourceDetector := newResourceDetector(utils, WithFS(fstest.MapFS{}))
+
+	r, err := systemdResourceDetector.Detect(t.Context())
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, r)
+
+	utils.AssertExpectations(t)
+}
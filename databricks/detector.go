@@ -0,0 +1,95 @@
+// Package databricks provides an OpenTelemetry detector for detecting
+// Databricks cluster resources.
+package databricks
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	runtimeVersionEnv = "DATABRICKS_RUNTIME_VERSION"
+	clusterIDEnv      = "DB_CLUSTER_ID"
+	clusterNameEnv    = "DB_CLUSTER_NAME"
+	localIPEnv        = "SPARK_LOCAL_IP"
+)
+
+const (
+	runtimeVersionKey = attribute.Key("databricks.runtime.version")
+	clusterIDKey      = attribute.Key("databricks.cluster.id")
+	clusterNameKey    = attribute.Key("databricks.cluster.name")
+)
+
+// There's no semantic convention value for Databricks, which runs on several
+// cloud providers.
+//
+//nolint:gochecknoglobals
+var platformDatabricks = semconv.CloudPlatformKey.String("databricks")
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type databricksDetectorUtils struct{}
+
+func (utils *databricksDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	runtimeVersion, _ := detector.utils.lookupEnv(runtimeVersionEnv)
+	if runtimeVersion == "" {
+		// Not a Databricks cluster
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		platformDatabricks,
+		runtimeVersionKey.String(runtimeVersion),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			clusterIDEnv,
+			clusterIDKey.String,
+		},
+		{
+			clusterNameEnv,
+			clusterNameKey.String,
+		},
+		{
+			// The address of this node of the cluster
+			localIPEnv,
+			func(v string) attribute.KeyValue {
+				return semconv.HostIP(v)
+			},
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// Databricks cluster resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(databricksDetectorUtils),
+	}
+}
@@ -0,0 +1,61 @@
+package databricks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestDatabricks(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", runtimeVersionEnv).Return("15.4", true).Once()
+	utils.On("lookupEnv", clusterIDEnv).Return("0123-456789-abcdefgh", true).Once()
+	utils.On("lookupEnv", clusterNameEnv).Return("analytics", true).Once()
+	utils.On("lookupEnv", localIPEnv).Return("10.139.64.4", true).Once()
+
+	databricksResourceDetector := resourceDetector{utils: utils}
+
+	r, err := databricksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		platformDatabricks,
+		runtimeVersionKey.String("15.4"),
+		clusterIDKey.String("0123-456789-abcdefgh"),
+		clusterNameKey.String("analytics"),
+		semconv.HostIP("10.139.64.4"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotDatabricks(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", runtimeVersionEnv).Return("", false).Once()
+
+	databricksResourceDetector := resourceDetector{utils: utils}
+
+	r, err := databricksResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
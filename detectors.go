@@ -0,0 +1,99 @@
+// Package detectors provides helpers for combining and using the
+// OpenTelemetry detectors in this repository.
+package detectors
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// ErrNotDetected is returned by the [resource.Detector] returned by [Require]
+// if the wrapped detector doesn't detect anything.
+var ErrNotDetected = errors.New("resource not detected")
+
+type result struct {
+	r   *resource.Resource
+	err error
+}
+
+// FirstCloud runs the cloud detectors concurrently and returns the first
+// non-empty resource detected, cancelling the context passed to the others.
+// This is faster than running them in turn for applications that could be
+// deployed to any one of several clouds. If no detector matches then an
+// empty resource is returned along with any errors.
+func FirstCloud(ctx context.Context, detectors ...resource.Detector) (*resource.Resource, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so the remaining detectors don't block once one matches
+	results := make(chan result, len(detectors))
+
+	for _, detector := range detectors {
+		go func() {
+			r, err := detector.Detect(ctx)
+			results <- result{r, err}
+		}()
+	}
+
+	var errs []error
+
+	for range detectors {
+		result := <-results
+		if result.err != nil {
+			errs = append(errs, result.err)
+
+			continue
+		}
+
+		if result.r != nil && result.r.Len() > 0 {
+			return result.r, nil
+		}
+	}
+
+	return resource.Empty(), errors.Join(errs...)
+}
+
+type requiredDetector struct {
+	detector resource.Detector
+}
+
+func (detector *requiredDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	r, err := detector.detector.Detect(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if r == nil || r.Len() == 0 {
+		return nil, ErrNotDetected
+	}
+
+	return r, nil
+}
+
+var _ resource.Detector = new(requiredDetector)
+
+// Require returns a [resource.Detector] that wraps the given detector and
+// returns [ErrNotDetected] if it returns an empty resource. Any error or
+// non-empty resource is returned unchanged. This allows a detector that isn't
+// expected to match to be distinguished from one that has failed.
+func Require(detector resource.Detector) resource.Detector {
+	return &requiredDetector{
+		detector: detector,
+	}
+}
+
+// DetectAttributes runs the [resource.Detector] and returns the detected
+// attributes as a flat slice, sorted by key, for use outside of OpenTelemetry.
+// If the detector returns a partial resource alongside an error then both the
+// partial attributes and the error are returned.
+func DetectAttributes(ctx context.Context, detector resource.Detector) ([]attribute.KeyValue, error) {
+	r, err := detector.Detect(ctx)
+	if r == nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return r.Attributes(), err //nolint:wrapcheck
+}
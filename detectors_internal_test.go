@@ -0,0 +1,220 @@
+package detectors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+var errTest = errors.New("test")
+
+// fakeDetector returns its resource, or error, after a delay unless the
+// context is cancelled first.
+type fakeDetector struct {
+	r         *resource.Resource
+	err       error
+	delay     time.Duration
+	cancelled chan struct{}
+}
+
+func newFakeDetector(r *resource.Resource, err error, delay time.Duration) *fakeDetector {
+	return &fakeDetector{
+		r:         r,
+		err:       err,
+		delay:     delay,
+		cancelled: make(chan struct{}),
+	}
+}
+
+func (detector *fakeDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	select {
+	case <-time.After(detector.delay):
+		return detector.r, detector.err
+	case <-ctx.Done():
+		close(detector.cancelled)
+
+		return nil, ctx.Err()
+	}
+}
+
+func TestFirstCloud(t *testing.T) {
+	t.Parallel()
+
+	aws := resource.NewWithAttributes(semconv.SchemaURL, semconv.CloudProviderAWS)
+
+	// Only one of the clouds matches, the others would take much longer
+	gcp := newFakeDetector(nil, nil, time.Hour)
+	azure := newFakeDetector(nil, nil, time.Hour)
+
+	r, err := FirstCloud(t.Context(),
+		gcp,
+		newFakeDetector(resource.Empty(), nil, 0),
+		newFakeDetector(aws, nil, 10*time.Millisecond),
+		azure,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, aws, r)
+
+	for _, detector := range []*fakeDetector{gcp, azure} {
+		select {
+		case <-detector.cancelled:
+		case <-time.After(time.Second):
+			assert.Fail(t, "detector not cancelled")
+		}
+	}
+}
+
+func TestFirstCloudNoMatch(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		detectors []resource.Detector
+		err       error
+	}{
+		{
+			name: "no match",
+			detectors: []resource.Detector{
+				newFakeDetector(resource.Empty(), nil, 0),
+				newFakeDetector(nil, nil, 0),
+			},
+		},
+		{
+			name: "error",
+			detectors: []resource.Detector{
+				newFakeDetector(resource.Empty(), nil, 0),
+				newFakeDetector(nil, errTest, 0),
+			},
+			err: errTest,
+		},
+		{
+			name: "no detectors",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := FirstCloud(t.Context(), table.detectors...)
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, resource.Empty(), r)
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	t.Parallel()
+
+	aws := resource.NewWithAttributes(semconv.SchemaURL, semconv.CloudProviderAWS)
+
+	tables := []struct {
+		name     string
+		detector resource.Detector
+		expected *resource.Resource
+		err      error
+	}{
+		{
+			name:     "detected",
+			detector: newFakeDetector(aws, nil, 0),
+			expected: aws,
+		},
+		{
+			name:     "empty",
+			detector: newFakeDetector(resource.Empty(), nil, 0),
+			err:      ErrNotDetected,
+		},
+		{
+			name:     "nil",
+			detector: newFakeDetector(nil, nil, 0),
+			err:      ErrNotDetected,
+		},
+		{
+			name:     "error",
+			detector: newFakeDetector(nil, errTest, 0),
+			err:      errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := Require(table.detector).Detect(t.Context())
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestDetectAttributes(t *testing.T) {
+	t.Parallel()
+
+	aws := resource.NewWithAttributes(semconv.SchemaURL, semconv.CloudRegion("eu-west-1"), semconv.CloudProviderAWS)
+
+	tables := []struct {
+		name     string
+		detector resource.Detector
+		expected []attribute.KeyValue
+		err      error
+	}{
+		{
+			name:     "detected",
+			detector: newFakeDetector(aws, nil, 0),
+			expected: []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudRegion("eu-west-1"),
+			},
+		},
+		{
+			name:     "empty",
+			detector: newFakeDetector(resource.Empty(), nil, 0),
+		},
+		{
+			name:     "partial",
+			detector: newFakeDetector(aws, errTest, 0),
+			expected: []attribute.KeyValue{
+				semconv.CloudProviderAWS,
+				semconv.CloudRegion("eu-west-1"),
+			},
+			err: errTest,
+		},
+		{
+			name:     "error",
+			detector: newFakeDetector(nil, errTest, 0),
+			err:      errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			attributes, err := DetectAttributes(t.Context(), table.detector)
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, table.expected, attributes)
+		})
+	}
+}
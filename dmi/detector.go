@@ -0,0 +1,150 @@
+// Package dmi provides an OpenTelemetry detector for detecting host
+// attributes from the SMBIOS/DMI information exposed by the Linux kernel.
+package dmi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const dmiDir = "sys/class/dmi/id"
+
+const (
+	productUUIDFile = "product_uuid"
+	sysVendorFile   = "sys_vendor"
+	productNameFile = "product_name"
+	boardSerialFile = "board_serial"
+)
+
+// There's no semantic convention for these.
+const (
+	hostManufacturerKey = attribute.Key("host.manufacturer")
+	hostModelKey        = attribute.Key("host.model")
+)
+
+// Values commonly left in the DMI tables by firmware vendors that don't
+// identify anything.
+//
+//nolint:gochecknoglobals
+var placeholders = []string{
+	"",
+	"0",
+	"00000000-0000-0000-0000-000000000000",
+	"ffffffff-ffff-ffff-ffff-ffffffffffff",
+	"default string",
+	"none",
+	"not applicable",
+	"not specified",
+	"system serial number",
+	"to be filled by o.e.m.",
+}
+
+type resourceDetector struct {
+	fsys fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	attributes := make([]attribute.KeyValue, 0, 3)
+
+	// The UUID and serial number are usually only readable by root
+	for _, s := range []struct {
+		files []string
+		fn    func(string) attribute.KeyValue
+	}{
+		{
+			[]string{productUUIDFile, boardSerialFile},
+			semconv.HostID,
+		},
+		{
+			[]string{sysVendorFile},
+			hostManufacturerKey.String,
+		},
+		{
+			[]string{productNameFile},
+			hostModelKey.String,
+		},
+	} {
+		for _, file := range s.files {
+			v, err := detector.readFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			if v != "" {
+				attributes = append(attributes, s.fn(v))
+
+				break
+			}
+		}
+	}
+
+	if len(attributes) == 0 {
+		// No DMI information, or it's not readable
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+func (detector *resourceDetector) readFile(file string) (string, error) {
+	name := path.Join(dmiDir, file)
+
+	b, err := fs.ReadFile(detector.fsys, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	v := string(bytes.TrimSpace(b))
+	if slices.Contains(placeholders, strings.ToLower(v)) {
+		return "", nil
+	}
+
+	if file == productUUIDFile {
+		v = strings.ToLower(v)
+	}
+
+	return v, nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read /sys/class/dmi/id. It should be
+// rooted at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect the host
+// ID, manufacturer, and model from the SMBIOS/DMI information.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		fsys: os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
@@ -0,0 +1,124 @@
+package dmi
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+var errTest = errors.New("test")
+
+// errorFS returns the given error when reading the named files.
+type errorFS struct {
+	fstest.MapFS
+	errs map[string]error
+}
+
+func (fsys errorFS) Open(name string) (fs.File, error) {
+	if err, ok := fsys.errs[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return fsys.MapFS.Open(name)
+}
+
+func (fsys errorFS) ReadFile(name string) ([]byte, error) {
+	if err, ok := fsys.errs[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return fsys.MapFS.ReadFile(name)
+}
+
+func TestDMI(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		fsys     fs.FS
+		expected *resource.Resource
+	}{
+		{
+			name: "dell",
+			fsys: fstest.MapFS{
+				"sys/class/dmi/id/product_uuid": &fstest.MapFile{Data: []byte("4C4C4544-0042-3510-8052-B4C04F4A4E32\n")},
+				"sys/class/dmi/id/sys_vendor":   &fstest.MapFile{Data: []byte("Dell Inc.\n")},
+				"sys/class/dmi/id/product_name": &fstest.MapFile{Data: []byte("PowerEdge R640\n")},
+				"sys/class/dmi/id/board_serial": &fstest.MapFile{Data: []byte(".5B4JN32.CNFCP0095M00LZ.\n")},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.HostID("4c4c4544-0042-3510-8052-b4c04f4a4e32"),
+				hostManufacturerKey.String("Dell Inc."),
+				hostModelKey.String("PowerEdge R640"),
+			),
+		},
+		{
+			name: "supermicro",
+			fsys: fstest.MapFS{
+				"sys/class/dmi/id/product_uuid": &fstest.MapFile{Data: []byte("00000000-0000-0000-0000-000000000000\n")},
+				"sys/class/dmi/id/sys_vendor":   &fstest.MapFile{Data: []byte("Supermicro\n")},
+				"sys/class/dmi/id/product_name": &fstest.MapFile{Data: []byte("To Be Filled By O.E.M.\n")},
+				"sys/class/dmi/id/board_serial": &fstest.MapFile{Data: []byte("WM18AS001234\n")},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.HostID("WM18AS001234"),
+				hostManufacturerKey.String("Supermicro"),
+			),
+		},
+		{
+			name: "unprivileged",
+			fsys: errorFS{
+				MapFS: fstest.MapFS{
+					"sys/class/dmi/id/sys_vendor":   &fstest.MapFile{Data: []byte("QEMU\n")},
+					"sys/class/dmi/id/product_name": &fstest.MapFile{Data: []byte("Standard PC (Q35 + ICH9, 2009)\n")},
+				},
+				errs: map[string]error{
+					"sys/class/dmi/id/product_uuid": fs.ErrPermission,
+					"sys/class/dmi/id/board_serial": fs.ErrPermission,
+				},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				hostManufacturerKey.String("QEMU"),
+				hostModelKey.String("Standard PC (Q35 + ICH9, 2009)"),
+			),
+		},
+		{
+			name:     "no dmi",
+			fsys:     fstest.MapFS{},
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			dmiResourceDetector := NewResourceDetector(WithFS(table.fsys))
+
+			r, err := dmiResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	dmiResourceDetector := NewResourceDetector(WithFS(errorFS{
+		MapFS: fstest.MapFS{},
+		errs: map[string]error{
+			"sys/class/dmi/id/product_uuid": errTest,
+		},
+	}))
+
+	r, err := dmiResourceDetector.Detect(t.Context())
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, r)
+}
@@ -0,0 +1,226 @@
+// Package socket provides an OpenTelemetry detector for detecting Docker
+// container resources by querying the Docker API over the Docker socket.
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	// DefaultSocket is the default path to the Docker socket.
+	DefaultSocket = "/var/run/docker.sock"
+
+	// The host is ignored when connecting over the socket
+	socketURL = "http://docker"
+)
+
+var errUnavailable = errors.New("docker API unavailable")
+
+// containerJSON is the subset of the container information returned by the
+// Docker API.
+type containerJSON struct {
+	ID     string `json:"Id"`    //nolint:tagliatelle
+	Name   string `json:"Name"`  //nolint:tagliatelle
+	Image  string `json:"Image"` //nolint:tagliatelle
+	Config struct {
+		Image string `json:"Image"` //nolint:tagliatelle
+	} `json:"Config"` //nolint:tagliatelle
+}
+
+type detectorUtils interface {
+	hostname() (string, error)
+	get(ctx context.Context, path string, v any) error
+	closeIdleConnections()
+}
+
+type dockerDetectorUtils struct {
+	client *http.Client
+	url    string
+}
+
+func (utils *dockerDetectorUtils) hostname() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("error getting hostname: %w", err)
+	}
+
+	return hostname, nil
+}
+
+func (utils *dockerDetectorUtils) get(ctx context.Context, path string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.url+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := utils.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// This includes the container not being found
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %q", errUnavailable, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (utils *dockerDetectorUtils) closeIdleConnections() {
+	utils.client.CloseIdleConnections()
+}
+
+type resourceDetector struct {
+	utils       detectorUtils
+	socket      string
+	containerID string
+}
+
+func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	// Docker sets the hostname to the short container ID by default
+	id := detector.containerID
+	if id == "" {
+		hostname, err := detector.utils.hostname()
+		if err != nil {
+			return nil, err
+		}
+
+		id = hostname
+	}
+
+	info := new(containerJSON)
+	if err := detector.utils.get(ctx, "/containers/"+url.PathEscape(id)+"/json", info); err != nil {
+		// No Docker socket, or not running in a Docker container
+		if errors.Is(err, errUnavailable) {
+			return resource.Empty(), nil
+		}
+
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.ContainerID(info.ID),
+		semconv.ContainerRuntimeName("docker"),
+	}
+
+	if name := strings.TrimPrefix(info.Name, "/"); name != "" {
+		attributes = append(attributes, semconv.ContainerName(name))
+	}
+
+	if info.Config.Image != "" {
+		name, tag := splitImage(info.Config.Image)
+
+		attributes = append(attributes, semconv.ContainerImageName(name))
+
+		if tag != "" {
+			attributes = append(attributes, semconv.ContainerImageTags(tag))
+		}
+	}
+
+	if info.Image != "" {
+		attributes = append(attributes, semconv.ContainerImageID(info.Image))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+// Close releases any idle connections to the Docker API. It is safe to call
+// more than once.
+func (detector *resourceDetector) Close() error {
+	if detector.utils != nil {
+		detector.utils.closeIdleConnections()
+	}
+
+	return nil
+}
+
+var (
+	_ resource.Detector = new(resourceDetector)
+	_ io.Closer         = new(resourceDetector)
+)
+
+// splitImage splits an image reference, such as "nginx:1.27" or
+// "registry.example.com:5000/app@sha256:...", into the image name and tag.
+func splitImage(image string) (string, string) {
+	image, _, _ = strings.Cut(image, "@")
+
+	// A colon before the last slash is part of the registry host
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+
+	return image, ""
+}
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithSocket sets the path to the Docker socket. The default is
+// [DefaultSocket].
+func WithSocket(socket string) Option {
+	return func(detector *resourceDetector) {
+		detector.socket = socket
+	}
+}
+
+// WithContainerID sets the ID, or name, of the container to query. This is
+// required if the container hostname has been changed from the default. The
+// default is to use the hostname.
+func WithContainerID(id string) Option {
+	return func(detector *resourceDetector) {
+		detector.containerID = id
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect Docker
+// container resources using the Docker API. The Docker socket needs to be
+// mounted in the container. The detector also implements [io.Closer] and
+// should be closed when the SDK is shut down.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		socket: DefaultSocket,
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+
+		return dialer.DialContext(ctx, "unix", detector.socket)
+	}
+
+	detector.utils = &dockerDetectorUtils{
+		client: &http.Client{
+			Transport: transport,
+		},
+		url: socketURL,
+	}
+
+	return detector
+}
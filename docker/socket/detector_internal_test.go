@@ -0,0 +1,202 @@
+package socket
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	testHostname    = "0123456789ab"
+	testContainerID = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	testContainer = `{
+  "Id": "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+  "Name": "/web",
+  "Image": "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+  "Config": {
+    "Hostname": "0123456789ab",
+    "Image": "registry.example.com:5000/web:1.2.3"
+  }
+}`
+)
+
+type testDetectorUtils struct {
+	*dockerDetectorUtils
+}
+
+func (utils *testDetectorUtils) hostname() (string, error) {
+	return testHostname, nil
+}
+
+func newTestDocker() http.Handler {
+	mux := http.NewServeMux()
+
+	// The Docker API accepts any unique prefix of the container ID
+	for _, id := range []string{testHostname, testContainerID} {
+		mux.HandleFunc("GET /containers/"+id+"/json", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testContainer))
+		})
+	}
+
+	return mux
+}
+
+var testExpected = resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{ //nolint:gochecknoglobals
+	semconv.ContainerID(testContainerID),
+	semconv.ContainerRuntimeName("docker"),
+	semconv.ContainerName("web"),
+	semconv.ContainerImageName("registry.example.com:5000/web"),
+	semconv.ContainerImageTags("1.2.3"),
+	semconv.ContainerImageID("sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"),
+}...)
+
+func TestDocker(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name        string
+		containerID string
+		expected    *resource.Resource
+	}{
+		{
+			name:     "hostname",
+			expected: testExpected,
+		},
+		{
+			name:        "container ID",
+			containerID: testContainerID,
+			expected:    testExpected,
+		},
+		{
+			name:        "not found",
+			containerID: "web",
+			expected:    resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(newTestDocker())
+			defer server.Close()
+
+			dockerResourceDetector := resourceDetector{
+				utils: &testDetectorUtils{
+					&dockerDetectorUtils{
+						client: server.Client(),
+						url:    server.URL,
+					},
+				},
+				containerID: table.containerID,
+			}
+
+			r, err := dockerResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(newTestDocker())
+	server.Listener = listener
+	server.Start()
+
+	defer server.Close()
+
+	r, err := NewResourceDetector(WithSocket(socket), WithContainerID(testHostname)).Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, testExpected, r)
+}
+
+func TestNoSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+
+	r, err := NewResourceDetector(WithSocket(socket), WithContainerID(testHostname)).Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestSplitImage(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		image, name, tag string
+	}{
+		{"nginx", "nginx", ""},
+		{"nginx:1.27", "nginx", "1.27"},
+		{"registry.example.com:5000/web", "registry.example.com:5000/web", ""},
+		{"registry.example.com:5000/web:1.2.3", "registry.example.com:5000/web", "1.2.3"},
+		{"web:1.2.3@sha256:0123456789abcdef", "web", "1.2.3"},
+	}
+
+	for _, table := range tables {
+		t.Run(table.image, func(t *testing.T) {
+			t.Parallel()
+
+			name, tag := splitImage(table.image)
+			assert.Equal(t, table.name, name)
+			assert.Equal(t, table.tag, tag)
+		})
+	}
+}
+
+type closeCountingTransport struct {
+	http.RoundTripper
+	closed atomic.Int32
+}
+
+func (transport *closeCountingTransport) CloseIdleConnections() {
+	transport.closed.Add(1)
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newTestDocker())
+	defer server.Close()
+
+	transport := &closeCountingTransport{
+		RoundTripper: server.Client().Transport,
+	}
+
+	dockerResourceDetector := resourceDetector{
+		utils: &dockerDetectorUtils{
+			client: &http.Client{
+				Transport: transport,
+			},
+			url: server.URL,
+		},
+		containerID: testContainerID,
+	}
+
+	_, err := dockerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	require.NoError(t, dockerResourceDetector.Close())
+	require.NoError(t, dockerResourceDetector.Close())
+	assert.Equal(t, int32(2), transport.closed.Load())
+
+	// Nothing has been allocated
+	require.NoError(t, new(resourceDetector).Close())
+}
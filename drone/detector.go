@@ -0,0 +1,85 @@
+// Package drone provides an OpenTelemetry detector for detecting Drone CI
+// build resources.
+package drone
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	buildNumberEnv = "DRONE_BUILD_NUMBER"
+	repoEnv        = "DRONE_REPO"
+	commitSHAEnv   = "DRONE_COMMIT_SHA"
+	stageNameEnv   = "DRONE_STAGE_NAME"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type droneDetectorUtils struct{}
+
+func (utils *droneDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	buildNumber, _ := detector.utils.lookupEnv(buildNumberEnv)
+	if buildNumber == "" {
+		// Not a Drone CI build
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineRunID(buildNumber),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			stageNameEnv,
+			semconv.CICDPipelineTaskName,
+		},
+		{
+			commitSHAEnv,
+			semconv.VCSRefHeadRevision,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	// Of the form "<owner>/<name>"
+	if v, _ := detector.utils.lookupEnv(repoEnv); v != "" {
+		if owner, name, ok := strings.Cut(v, "/"); ok {
+			attributes = append(attributes, semconv.VCSOwnerName(owner), semconv.VCSRepositoryName(name))
+		} else {
+			attributes = append(attributes, semconv.VCSRepositoryName(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Drone CI
+// build resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(droneDetectorUtils),
+	}
+}
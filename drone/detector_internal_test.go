@@ -0,0 +1,61 @@
+package drone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestDrone(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildNumberEnv).Return("22", true).Once()
+	utils.On("lookupEnv", stageNameEnv).Return("build", true).Once()
+	utils.On("lookupEnv", commitSHAEnv).Return("bcdd4bf0245c82c060407b3b24b9b87301d15ac1", true).Once()
+	utils.On("lookupEnv", repoEnv).Return("octocat/hello-world", true).Once()
+
+	droneResourceDetector := resourceDetector{utils: utils}
+
+	r, err := droneResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("22"),
+		semconv.CICDPipelineTaskName("build"),
+		semconv.VCSRefHeadRevision("bcdd4bf0245c82c060407b3b24b9b87301d15ac1"),
+		semconv.VCSOwnerName("octocat"),
+		semconv.VCSRepositoryName("hello-world"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotDrone(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildNumberEnv).Return("", false).Once()
+
+	droneResourceDetector := resourceDetector{utils: utils}
+
+	r, err := droneResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
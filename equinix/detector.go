@@ -0,0 +1,184 @@
+// Package equinix provides an OpenTelemetry detector for detecting Equinix
+// Metal resources.
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const metadataURL = "https://metadata.platformequinix.com/metadata"
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = 100 * time.Millisecond
+)
+
+var (
+	errUnavailable = errors.New("metadata unavailable")
+	errTransient   = errors.New("transient error")
+)
+
+type metadata struct {
+	ID       string `json:"id"`
+	Hostname string `json:"hostname"`
+	Plan     string `json:"plan"`
+	Facility string `json:"facility"`
+	Metro    string `json:"metro"`
+}
+
+type detectorUtils interface {
+	getMetadata(ctx context.Context) (*metadata, error)
+}
+
+type equinixDetectorUtils struct {
+	client   *http.Client
+	url      string
+	attempts int
+	backoff  time.Duration
+}
+
+// getMetadata fetches the device metadata, retrying on server errors from the
+// Equinix Metal metadata service.
+func (utils *equinixDetectorUtils) getMetadata(ctx context.Context) (*metadata, error) {
+	for attempt := 1; ; attempt++ {
+		m, err := utils.fetchMetadata(ctx)
+		if err == nil || !errors.Is(err, errTransient) || attempt >= utils.attempts {
+			return m, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(utils.backoff):
+		}
+	}
+}
+
+func (utils *equinixDetectorUtils) fetchMetadata(ctx context.Context) (*metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := utils.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// The metadata service may not be ready yet
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: %w: unexpected status %q", errUnavailable, errTransient, resp.Status)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %q", errUnavailable, resp.Status)
+	}
+
+	m := new(metadata)
+	if err := json.NewDecoder(resp.Body).Decode(m); err != nil {
+		return nil, fmt.Errorf("error decoding metadata: %w", err)
+	}
+
+	return m, nil
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	m, err := detector.utils.getMetadata(ctx)
+	if err != nil {
+		// Not on Equinix Metal, or the metadata service is unreachable
+		if errors.Is(err, errUnavailable) {
+			return resource.Empty(), nil
+		}
+
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderKey.String("equinix_metal"),
+	}
+
+	for _, s := range []struct {
+		value string
+		fn    func(string) attribute.KeyValue
+	}{
+		{
+			m.Metro,
+			semconv.CloudRegion,
+		},
+		{
+			m.Facility,
+			semconv.CloudAvailabilityZone,
+		},
+		{
+			m.ID,
+			semconv.HostID,
+		},
+		{
+			m.Hostname,
+			semconv.HostName,
+		},
+		{
+			m.Plan,
+			semconv.HostType,
+		},
+	} {
+		if s.value != "" {
+			attributes = append(attributes, s.fn(s.value))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*equinixDetectorUtils)
+
+// WithMetadataRetries sets the number of requests made to the Equinix Metal
+// metadata service and the delay between them. A request is only repeated if
+// the service returned a server error. The default is 3 attempts with a
+// backoff of 100ms.
+func WithMetadataRetries(attempts int, backoff time.Duration) Option {
+	return func(utils *equinixDetectorUtils) {
+		utils.attempts = attempts
+		utils.backoff = backoff
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect Equinix
+// Metal resources.
+func NewResourceDetector(options ...Option) resource.Detector {
+	utils := &equinixDetectorUtils{
+		client:   http.DefaultClient,
+		url:      metadataURL,
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+
+	for _, option := range options {
+		option(utils)
+	}
+
+	return &resourceDetector{
+		utils: utils,
+	}
+}
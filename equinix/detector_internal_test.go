@@ -0,0 +1,163 @@
+package equinix
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const testMetadata = `{
+  "id": "6f2c2b3a-1d4e-4f5a-9b8c-7d6e5f4a3b2c",
+  "hostname": "worker-1",
+  "iqn": "iqn.2026-10.net.packet:device.6f2c2b3a",
+  "operating_system": {
+    "slug": "ubuntu_24_04",
+    "distro": "ubuntu",
+    "version": "24.04"
+  },
+  "plan": "c3.small.x86",
+  "class": "c3.small.x86",
+  "facility": "sv15",
+  "metro": "sv",
+  "tags": [],
+  "ssh_keys": []
+}`
+
+func TestEquinix(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metadata", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testMetadata))
+	}))
+	defer server.Close()
+
+	equinixResourceDetector := resourceDetector{
+		utils: &equinixDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/metadata",
+		},
+	}
+
+	r, err := equinixResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderKey.String("equinix_metal"),
+		semconv.CloudRegion("sv"),
+		semconv.CloudAvailabilityZone("sv15"),
+		semconv.HostID("6f2c2b3a-1d4e-4f5a-9b8c-7d6e5f4a3b2c"),
+		semconv.HostName("worker-1"),
+		semconv.HostType("c3.small.x86"),
+	}...), r)
+}
+
+func TestNotEquinix(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	equinixResourceDetector := resourceDetector{
+		utils: &equinixDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/metadata",
+		},
+	}
+
+	r, err := equinixResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	equinixResourceDetector := resourceDetector{
+		utils: &equinixDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/metadata",
+		},
+	}
+
+	r, err := equinixResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestMetadataRetries(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		attempts int
+		expected *resource.Resource
+	}{
+		{
+			name:     "retried",
+			attempts: 3,
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderKey.String("equinix_metal"),
+				semconv.CloudRegion("sv"),
+				semconv.CloudAvailabilityZone("sv15"),
+				semconv.HostID("6f2c2b3a-1d4e-4f5a-9b8c-7d6e5f4a3b2c"),
+				semconv.HostName("worker-1"),
+				semconv.HostType("c3.small.x86"),
+			}...),
+		},
+		{
+			name:     "too few attempts",
+			attempts: 2,
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(testMetadata))
+			})
+
+			// The metadata service fails twice before it's ready
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			equinixResourceDetector := NewResourceDetector(
+				WithMetadataRetries(table.attempts, time.Millisecond),
+				func(utils *equinixDetectorUtils) {
+					utils.client = server.Client()
+					utils.url = server.URL + "/metadata"
+				},
+			)
+
+			r, err := equinixResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+			assert.Equal(t, int32(table.attempts), requests.Load())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package cloudbuild provides an OpenTelemetry detector for detecting Google
+// Cloud Build resources.
+package cloudbuild
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	buildIDEnv     = "BUILD_ID"
+	projectIDEnv   = "PROJECT_ID"
+	commitSHAEnv   = "COMMIT_SHA"
+	repoNameEnv    = "REPO_NAME"
+	triggerNameEnv = "TRIGGER_NAME"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type cloudBuildDetectorUtils struct{}
+
+func (utils *cloudBuildDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	// BUILD_ID on its own is too generic, Jenkins uses it too
+	buildID, _ := detector.utils.lookupEnv(buildIDEnv)
+	projectID, _ := detector.utils.lookupEnv(projectIDEnv)
+
+	if buildID == "" || projectID == "" {
+		// Not a Cloud Build build
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudAccountID(projectID),
+		semconv.CICDPipelineRunID(buildID),
+	}
+
+	// These are only populated for builds started by a trigger
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			triggerNameEnv,
+			semconv.CICDPipelineName,
+		},
+		{
+			repoNameEnv,
+			semconv.VCSRepositoryName,
+		},
+		{
+			commitSHAEnv,
+			semconv.VCSRefHeadRevision,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Google
+// Cloud Build resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(cloudBuildDetectorUtils),
+	}
+}
@@ -0,0 +1,88 @@
+package cloudbuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestCloudBuild(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("4f7c2e1a-5b3d-4c8e-9f0a-1b2c3d4e5f60", true).Once()
+	utils.On("lookupEnv", projectIDEnv).Return("my-project", true).Once()
+	utils.On("lookupEnv", triggerNameEnv).Return("deploy-on-push", true).Once()
+	utils.On("lookupEnv", repoNameEnv).Return("detectors", true).Once()
+	utils.On("lookupEnv", commitSHAEnv).Return("ffac537e6cbbf934b08745a378932722df287a53", true).Once()
+
+	cloudBuildResourceDetector := resourceDetector{utils: utils}
+
+	r, err := cloudBuildResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudAccountID("my-project"),
+		semconv.CICDPipelineRunID("4f7c2e1a-5b3d-4c8e-9f0a-1b2c3d4e5f60"),
+		semconv.CICDPipelineName("deploy-on-push"),
+		semconv.VCSRepositoryName("detectors"),
+		semconv.VCSRefHeadRevision("ffac537e6cbbf934b08745a378932722df287a53"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotCloudBuild(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		buildID   string
+		projectID string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:    "build ID only",
+			buildID: "42",
+		},
+		{
+			name:      "project ID only",
+			projectID: "my-project",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", buildIDEnv).Return(table.buildID, table.buildID != "").Once()
+			utils.On("lookupEnv", projectIDEnv).Return(table.projectID, table.projectID != "").Once()
+
+			cloudBuildResourceDetector := resourceDetector{utils: utils}
+
+			r, err := cloudBuildResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.Empty(), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Package cloudfunctions provides an OpenTelemetry detector for detecting
+// Google Cloud Functions resources.
+package cloudfunctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	functionTargetEnv        = "FUNCTION_TARGET"
+	functionSignatureTypeEnv = "FUNCTION_SIGNATURE_TYPE"
+	functionNameEnv          = "FUNCTION_NAME"
+	functionRegionEnv        = "FUNCTION_REGION"
+	functionVersionEnv       = "X_GOOGLE_FUNCTION_VERSION"
+	serviceEnv               = "K_SERVICE"
+	revisionEnv              = "K_REVISION"
+	configurationEnv         = "K_CONFIGURATION"
+	projectEnv               = "GOOGLE_CLOUD_PROJECT"
+	legacyProjectEnv         = "GCP_PROJECT"
+
+	metadataURL = "http://metadata.google.internal/computeMetadata/v1/"
+)
+
+const generationKey = attribute.Key("gcp.cloud_functions.generation")
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+	getMetadata(ctx context.Context, path string) (string, error)
+}
+
+type cloudFunctionsDetectorUtils struct {
+	client *http.Client
+	url    string
+}
+
+func (utils *cloudFunctionsDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (utils *cloudFunctionsDetectorUtils) getMetadata(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.url+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := utils.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error querying metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %q", errUnexpectedStatus, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading metadata: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) lookupFirst(keys ...string) string {
+	for _, key := range keys {
+		if v, _ := detector.utils.lookupEnv(key); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	if v, _ := detector.utils.lookupEnv(functionTargetEnv); v == "" {
+		// Not a Cloud Function
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPCloudFunctions,
+	}
+
+	// 2nd gen functions are Cloud Run services under the hood
+	generation := 1
+	if v, _ := detector.utils.lookupEnv(configurationEnv); v != "" {
+		generation = 2
+	}
+
+	attributes = append(attributes, generationKey.Int(generation))
+
+	for _, s := range []struct {
+		keys []string
+		fn   func(string) attribute.KeyValue
+	}{
+		{
+			[]string{serviceEnv, functionNameEnv},
+			semconv.FaaSName,
+		},
+		{
+			[]string{revisionEnv, functionVersionEnv},
+			semconv.FaaSVersion,
+		},
+		{
+			[]string{projectEnv, legacyProjectEnv},
+			semconv.CloudAccountID,
+		},
+	} {
+		if v := detector.lookupFirst(s.keys...); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	if v, _ := detector.utils.lookupEnv(functionSignatureTypeEnv); v == "http" {
+		attributes = append(attributes, semconv.FaaSTriggerHTTP)
+	}
+
+	region, _ := detector.utils.lookupEnv(functionRegionEnv)
+	if region == "" {
+		// Of the form "projects/<number>/regions/<region>"
+		if v, err := detector.utils.getMetadata(ctx, "instance/region"); err == nil {
+			region = path.Base(v)
+		}
+	}
+
+	if region != "" {
+		attributes = append(attributes, semconv.CloudRegion(region))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Google
+// Cloud Functions resources, both 1st and 2nd gen.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: &cloudFunctionsDetectorUtils{
+			client: http.DefaultClient,
+			url:    metadataURL,
+		},
+	}
+}
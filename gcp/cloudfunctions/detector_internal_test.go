@@ -0,0 +1,158 @@
+package cloudfunctions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+var errTest = errors.New("test")
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func (utils *mockDetectorUtils) getMetadata(ctx context.Context, path string) (string, error) {
+	args := utils.Called(ctx, path)
+
+	return args.String(0), args.Error(1)
+}
+
+func TestSecondGen(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", functionTargetEnv).Return("HelloHTTP", true).Once()
+	utils.On("lookupEnv", functionSignatureTypeEnv).Return("http", true).Once()
+	utils.On("lookupEnv", serviceEnv).Return("hello-http", true).Once()
+	utils.On("lookupEnv", revisionEnv).Return("hello-http-00002-abc", true).Once()
+	utils.On("lookupEnv", configurationEnv).Return("hello-http", true).Once()
+	utils.On("lookupEnv", projectEnv).Return("my-project", true).Once()
+	utils.On("lookupEnv", mock.Anything).Return("", false)
+	utils.On("getMetadata", mock.Anything, "instance/region").Return("projects/123456789/regions/europe-west2", nil).Once()
+
+	cloudFunctionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := cloudFunctionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPCloudFunctions,
+		generationKey.Int(2),
+		semconv.FaaSName("hello-http"),
+		semconv.FaaSVersion("hello-http-00002-abc"),
+		semconv.CloudAccountID("my-project"),
+		semconv.FaaSTriggerHTTP,
+		semconv.CloudRegion("europe-west2"),
+	), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestFirstGen(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", functionTargetEnv).Return("HelloPubSub", true).Once()
+	utils.On("lookupEnv", functionSignatureTypeEnv).Return("event", true).Once()
+	utils.On("lookupEnv", functionNameEnv).Return("hello-pubsub", true).Once()
+	utils.On("lookupEnv", functionVersionEnv).Return("7", true).Once()
+	utils.On("lookupEnv", functionRegionEnv).Return("us-central1", true).Once()
+	utils.On("lookupEnv", legacyProjectEnv).Return("my-project", true).Once()
+	utils.On("lookupEnv", mock.Anything).Return("", false)
+
+	cloudFunctionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := cloudFunctionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPCloudFunctions,
+		generationKey.Int(1),
+		semconv.FaaSName("hello-pubsub"),
+		semconv.FaaSVersion("7"),
+		semconv.CloudAccountID("my-project"),
+		semconv.CloudRegion("us-central1"),
+	), r)
+
+	utils.AssertExpectations(t)
+	utils.AssertNotCalled(t, "getMetadata", mock.Anything, mock.Anything)
+}
+
+func TestNoMetadata(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", functionTargetEnv).Return("HelloHTTP", true).Once()
+	utils.On("lookupEnv", serviceEnv).Return("hello-http", true).Once()
+	utils.On("lookupEnv", mock.Anything).Return("", false)
+	utils.On("getMetadata", mock.Anything, "instance/region").Return("", errTest).Once()
+
+	cloudFunctionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := cloudFunctionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPCloudFunctions,
+		generationKey.Int(1),
+		semconv.FaaSName("hello-http"),
+	), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotCloudFunctions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", functionTargetEnv).Return("", false).Once()
+
+	cloudFunctionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := cloudFunctionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestGetMetadata(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		assert.Equal(t, "/computeMetadata/v1/instance/region", r.URL.Path)
+
+		_, _ = w.Write([]byte("projects/123456789/regions/europe-west2"))
+	}))
+	defer server.Close()
+
+	utils := &cloudFunctionsDetectorUtils{
+		client: server.Client(),
+		url:    server.URL + "/computeMetadata/v1/",
+	}
+
+	v, err := utils.getMetadata(t.Context(), "instance/region")
+	require.NoError(t, err)
+	assert.Equal(t, "projects/123456789/regions/europe-west2", v)
+}
@@ -0,0 +1,82 @@
+// Package actions provides an OpenTelemetry detector for detecting GitHub
+// Actions runner resources.
+package actions
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	runIDEnv      = "GITHUB_RUN_ID"
+	runNumberEnv  = "GITHUB_RUN_NUMBER"
+	workflowEnv   = "GITHUB_WORKFLOW"
+	repositoryEnv = "GITHUB_REPOSITORY"
+	shaEnv        = "GITHUB_SHA"
+)
+
+const runNumberKey = attribute.Key("github.actions.run_number")
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type actionsDetectorUtils struct{}
+
+func (utils *actionsDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	runID, _ := detector.utils.lookupEnv(runIDEnv)
+	if runID == "" {
+		// Not a GitHub Actions run
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineRunID(runID),
+	}
+
+	if v, _ := detector.utils.lookupEnv(runNumberEnv); v != "" {
+		attributes = append(attributes, runNumberKey.String(v))
+	}
+
+	if v, _ := detector.utils.lookupEnv(workflowEnv); v != "" {
+		attributes = append(attributes, semconv.CICDPipelineName(v), semconv.ServiceName(v))
+	}
+
+	// Of the form "<owner>/<repository>"
+	if v, _ := detector.utils.lookupEnv(repositoryEnv); v != "" {
+		if owner, name, ok := strings.Cut(v, "/"); ok {
+			attributes = append(attributes, semconv.VCSOwnerName(owner), semconv.VCSRepositoryName(name))
+		} else {
+			attributes = append(attributes, semconv.VCSRepositoryName(v))
+		}
+	}
+
+	if v, _ := detector.utils.lookupEnv(shaEnv); v != "" {
+		attributes = append(attributes, semconv.VCSRefHeadRevision(v))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect GitHub
+// Actions runner resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(actionsDetectorUtils),
+	}
+}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestActions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", runIDEnv).Return("1658821493", true).Once()
+	utils.On("lookupEnv", runNumberEnv).Return("42", true).Once()
+	utils.On("lookupEnv", workflowEnv).Return("Build", true).Once()
+	utils.On("lookupEnv", repositoryEnv).Return("bodgit/detectors", true).Once()
+	utils.On("lookupEnv", shaEnv).Return("ffac537e6cbbf934b08745a378932722df287a53", true).Once()
+
+	actionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := actionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("1658821493"),
+		runNumberKey.String("42"),
+		semconv.CICDPipelineName("Build"),
+		semconv.ServiceName("Build"),
+		semconv.VCSOwnerName("bodgit"),
+		semconv.VCSRepositoryName("detectors"),
+		semconv.VCSRefHeadRevision("ffac537e6cbbf934b08745a378932722df287a53"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotActions(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", runIDEnv).Return("", false).Once()
+
+	actionsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := actionsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
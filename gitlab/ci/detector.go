@@ -0,0 +1,91 @@
+// Package ci provides an OpenTelemetry detector for detecting GitLab CI
+// runner resources.
+package ci
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	pipelineIDEnv  = "CI_PIPELINE_ID"
+	jobIDEnv       = "CI_JOB_ID"
+	projectPathEnv = "CI_PROJECT_PATH"
+	commitSHAEnv   = "CI_COMMIT_SHA"
+	runnerIDEnv    = "CI_RUNNER_ID"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type ciDetectorUtils struct{}
+
+func (utils *ciDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	pipelineID, _ := detector.utils.lookupEnv(pipelineIDEnv)
+	if pipelineID == "" {
+		// Not a GitLab CI pipeline
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineRunID(pipelineID),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			jobIDEnv,
+			semconv.CICDPipelineTaskRunID,
+		},
+		{
+			commitSHAEnv,
+			semconv.VCSRefHeadRevision,
+		},
+		{
+			runnerIDEnv,
+			semconv.CICDWorkerID,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	// Of the form "<namespace>/<project>", where the namespace can include
+	// any number of nested groups
+	if v, _ := detector.utils.lookupEnv(projectPathEnv); v != "" {
+		if i := strings.LastIndex(v, "/"); i >= 0 {
+			attributes = append(attributes, semconv.VCSOwnerName(v[:i]), semconv.VCSRepositoryName(v[i+1:]))
+		} else {
+			attributes = append(attributes, semconv.VCSRepositoryName(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect GitLab CI
+// runner resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(ciDetectorUtils),
+	}
+}
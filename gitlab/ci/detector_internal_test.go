@@ -0,0 +1,63 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestGitLabCI(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", pipelineIDEnv).Return("1048576", true).Once()
+	utils.On("lookupEnv", jobIDEnv).Return("7340032", true).Once()
+	utils.On("lookupEnv", projectPathEnv).Return("gitlab-org/ci-cd/runner", true).Once()
+	utils.On("lookupEnv", commitSHAEnv).Return("ffac537e6cbbf934b08745a378932722df287a53", true).Once()
+	utils.On("lookupEnv", runnerIDEnv).Return("12270", true).Once()
+
+	ciResourceDetector := resourceDetector{utils: utils}
+
+	r, err := ciResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("1048576"),
+		semconv.CICDPipelineTaskRunID("7340032"),
+		semconv.VCSRefHeadRevision("ffac537e6cbbf934b08745a378932722df287a53"),
+		semconv.CICDWorkerID("12270"),
+		semconv.VCSOwnerName("gitlab-org/ci-cd"),
+		semconv.VCSRepositoryName("runner"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotGitLabCI(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", pipelineIDEnv).Return("", false).Once()
+
+	ciResourceDetector := resourceDetector{utils: utils}
+
+	r, err := ciResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
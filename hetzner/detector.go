@@ -0,0 +1,179 @@
+// Package hetzner provides an OpenTelemetry detector for detecting Hetzner
+// Cloud server resources.
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const metadataURL = "http://169.254.169.254/hetzner/v1/metadata/"
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = 100 * time.Millisecond
+)
+
+var (
+	errUnavailable = errors.New("metadata unavailable")
+	errTransient   = errors.New("transient error")
+)
+
+type detectorUtils interface {
+	getMetadata(ctx context.Context, path string) (string, error)
+}
+
+type hetznerDetectorUtils struct {
+	client   *http.Client
+	url      string
+	attempts int
+	backoff  time.Duration
+}
+
+// getMetadata fetches a single metadata value, retrying whilst the metadata
+// service returns server errors, which it can do while a Hetzner Cloud server
+// is still booting.
+func (utils *hetznerDetectorUtils) getMetadata(ctx context.Context, path string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		v, err := utils.fetchMetadata(ctx, path)
+		if err == nil || !errors.Is(err, errTransient) || attempt >= utils.attempts {
+			return v, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(utils.backoff):
+		}
+	}
+}
+
+func (utils *hetznerDetectorUtils) fetchMetadata(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.url+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := utils.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// The metadata service may not be ready yet
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("%w: %w: unexpected status %q", errUnavailable, errTransient, resp.Status)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: unexpected status %q", errUnavailable, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading metadata: %w", err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	instanceID, err := detector.utils.getMetadata(ctx, "instance-id")
+	if err != nil {
+		// Not on Hetzner Cloud, or the metadata service is unreachable
+		if errors.Is(err, errUnavailable) {
+			return resource.Empty(), nil
+		}
+
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderHetzner,
+		semconv.CloudPlatformHetznerCloudServer,
+		semconv.HostID(instanceID),
+	}
+
+	for _, s := range []struct {
+		path string
+		fn   func(string) attribute.KeyValue
+	}{
+		{
+			"region",
+			semconv.CloudRegion,
+		},
+		{
+			// The datacenter, such as "fsn1-dc14"
+			"availability-zone",
+			semconv.CloudAvailabilityZone,
+		},
+		{
+			"hostname",
+			semconv.HostName,
+		},
+	} {
+		v, err := detector.utils.getMetadata(ctx, s.path)
+		if err != nil {
+			return nil, err
+		}
+
+		if v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*hetznerDetectorUtils)
+
+// WithMetadataRetries sets how many times the Hetzner Cloud metadata service is
+// asked for the server metadata and how long to wait between each attempt.
+// Only server errors are retried; if the metadata service is unreachable then
+// the server isn't on Hetzner Cloud and detection stops immediately. The
+// default is 3 attempts with a backoff of 100ms.
+func WithMetadataRetries(attempts int, backoff time.Duration) Option {
+	return func(utils *hetznerDetectorUtils) {
+		utils.attempts = attempts
+		utils.backoff = backoff
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect Hetzner
+// Cloud server resources.
+func NewResourceDetector(options ...Option) resource.Detector {
+	utils := &hetznerDetectorUtils{
+		client:   http.DefaultClient,
+		url:      metadataURL,
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+
+	for _, option := range options {
+		option(utils)
+	}
+
+	return &resourceDetector{
+		utils: utils,
+	}
+}
@@ -0,0 +1,159 @@
+package hetzner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+func newTestMetadata() http.Handler {
+	mux := http.NewServeMux()
+
+	for path, value := range map[string]string{
+		"instance-id":       "12345678",
+		"region":            "eu-central",
+		"availability-zone": "fsn1-dc14",
+		"hostname":          "my-server",
+	} {
+		mux.HandleFunc("GET /hetzner/v1/metadata/"+path, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(value))
+		})
+	}
+
+	return mux
+}
+
+func TestHetzner(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newTestMetadata())
+	defer server.Close()
+
+	hetznerResourceDetector := resourceDetector{
+		utils: &hetznerDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/hetzner/v1/metadata/",
+		},
+	}
+
+	r, err := hetznerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderHetzner,
+		semconv.CloudPlatformHetznerCloudServer,
+		semconv.CloudRegion("eu-central"),
+		semconv.CloudAvailabilityZone("fsn1-dc14"),
+		semconv.HostID("12345678"),
+		semconv.HostName("my-server"),
+	}...), r)
+}
+
+func TestNotHetzner(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	hetznerResourceDetector := resourceDetector{
+		utils: &hetznerDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/hetzner/v1/metadata/",
+		},
+	}
+
+	r, err := hetznerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	hetznerResourceDetector := resourceDetector{
+		utils: &hetznerDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/hetzner/v1/metadata/",
+		},
+	}
+
+	r, err := hetznerResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestMetadataRetries(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		attempts int
+		requests int32
+		expected *resource.Resource
+	}{
+		{
+			name:     "retried",
+			attempts: 3,
+			requests: 6,
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderHetzner,
+				semconv.CloudPlatformHetznerCloudServer,
+				semconv.CloudRegion("eu-central"),
+				semconv.CloudAvailabilityZone("fsn1-dc14"),
+				semconv.HostID("12345678"),
+				semconv.HostName("my-server"),
+			}...),
+		},
+		{
+			name:     "too few attempts",
+			attempts: 2,
+			requests: 2,
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+
+			handler := newTestMetadata()
+
+			// The metadata service fails twice before it's ready
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			hetznerResourceDetector := NewResourceDetector(
+				WithMetadataRetries(table.attempts, time.Millisecond),
+				func(utils *hetznerDetectorUtils) {
+					utils.client = server.Client()
+					utils.url = server.URL + "/hetzner/v1/metadata/"
+				},
+			)
+
+			r, err := hetznerResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+			assert.Equal(t, table.requests, requests.Load())
+		})
+	}
+}
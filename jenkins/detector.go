@@ -0,0 +1,95 @@
+// Package jenkins provides an OpenTelemetry detector for detecting Jenkins
+// agent resources.
+package jenkins
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	jenkinsURLEnv     = "JENKINS_URL"
+	buildIDEnv        = "BUILD_ID"
+	buildNumberEnv    = "BUILD_NUMBER"
+	jobNameEnv        = "JOB_NAME"
+	executorNumberEnv = "EXECUTOR_NUMBER"
+	nodeNameEnv       = "NODE_NAME"
+)
+
+const (
+	buildNumberKey    = attribute.Key("jenkins.build.number")
+	executorNumberKey = attribute.Key("jenkins.executor.number")
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type jenkinsDetectorUtils struct{}
+
+func (utils *jenkinsDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	// Other CI systems also set BUILD_ID so it's not enough on its own
+	jenkinsURL, _ := detector.utils.lookupEnv(jenkinsURLEnv)
+	buildID, _ := detector.utils.lookupEnv(buildIDEnv)
+
+	if jenkinsURL == "" || buildID == "" {
+		// Not a Jenkins build
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineRunID(buildID),
+	}
+
+	// The full name of the job, including any folders, such as "folder/job"
+	if jobName, _ := detector.utils.lookupEnv(jobNameEnv); jobName != "" {
+		attributes = append(attributes, semconv.CICDPipelineName(jobName), semconv.ServiceName(jobName))
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			buildNumberEnv,
+			buildNumberKey.String,
+		},
+		{
+			// Either "built-in" or the name of the agent
+			nodeNameEnv,
+			semconv.CICDWorkerName,
+		},
+		{
+			executorNumberEnv,
+			executorNumberKey.String,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Jenkins
+// agent resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(jenkinsDetectorUtils),
+	}
+}
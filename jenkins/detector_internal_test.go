@@ -0,0 +1,105 @@
+package jenkins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestJenkins(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", jenkinsURLEnv).Return("https://jenkins.example.com/", true).Once()
+	utils.On("lookupEnv", buildIDEnv).Return("42", true).Once()
+	utils.On("lookupEnv", buildNumberEnv).Return("42", true).Once()
+	utils.On("lookupEnv", jobNameEnv).Return("platform/deploy", true).Once()
+	utils.On("lookupEnv", nodeNameEnv).Return("linux-agent-1", true).Once()
+	utils.On("lookupEnv", executorNumberEnv).Return("0", true).Once()
+
+	jenkinsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := jenkinsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("42"),
+		buildNumberKey.String("42"),
+		semconv.CICDPipelineName("platform/deploy"),
+		semconv.ServiceName("platform/deploy"),
+		semconv.CICDWorkerName("linux-agent-1"),
+		executorNumberKey.String("0"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestJenkinsPartial(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", jenkinsURLEnv).Return("https://jenkins.example.com/", true).Once()
+	utils.On("lookupEnv", buildIDEnv).Return("42", true).Once()
+	utils.On("lookupEnv", mock.Anything).Return("", false)
+
+	jenkinsResourceDetector := resourceDetector{utils: utils}
+
+	r, err := jenkinsResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("42"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotJenkins(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name       string
+		jenkinsURL string
+		buildID    string
+	}{
+		{
+			name:    "no url",
+			buildID: "42",
+		},
+		{
+			name:       "no build id",
+			jenkinsURL: "https://jenkins.example.com/",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", jenkinsURLEnv).Return(table.jenkinsURL, table.jenkinsURL != "").Once()
+			utils.On("lookupEnv", buildIDEnv).Return(table.buildID, table.buildID != "").Once()
+
+			jenkinsResourceDetector := resourceDetector{utils: utils}
+
+			r, err := jenkinsResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.Empty(), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
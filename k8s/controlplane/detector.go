@@ -0,0 +1,83 @@
+// Package controlplane provides an OpenTelemetry detector for detecting
+// Kubernetes control-plane components running as static pods.
+package controlplane
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	// PodNameEnv is the environment variable that should be populated with
+	// the pod name using the downward API, i.e. from the `metadata.name`
+	// field.
+	PodNameEnv = "POD_NAME"
+
+	// ConfigSourceEnv is the environment variable that should be populated
+	// with the `kubernetes.io/config.source` annotation using the downward
+	// API. It is "file" for static pods.
+	ConfigSourceEnv = "POD_CONFIG_SOURCE"
+)
+
+const componentKey = attribute.Key("k8s.component")
+
+//nolint:gochecknoglobals
+var components = []struct {
+	prefix    string
+	component string
+}{
+	{"kube-apiserver-", "apiserver"},
+	{"kube-controller-manager-", "controller-manager"},
+	{"kube-scheduler-", "scheduler"},
+	{"etcd-", "etcd"},
+}
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type controlPlaneDetectorUtils struct{}
+
+func (utils *controlPlaneDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	// Not a static pod
+	if v, _ := detector.utils.lookupEnv(ConfigSourceEnv); v != "file" {
+		return resource.Empty(), nil
+	}
+
+	name, _ := detector.utils.lookupEnv(PodNameEnv)
+
+	for _, c := range components {
+		// Static pods are named after the component with the node name appended
+		if strings.HasPrefix(name, c.prefix) && len(name) > len(c.prefix) {
+			return resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SPodName(name),
+				componentKey.String(c.component),
+			), nil
+		}
+	}
+
+	return resource.Empty(), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// Kubernetes control-plane components.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(controlPlaneDetectorUtils),
+	}
+}
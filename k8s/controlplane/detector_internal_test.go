@@ -0,0 +1,124 @@
+package controlplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestControlPlane(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name      string
+		pod       string
+		component string
+	}{
+		{
+			"apiserver",
+			"kube-apiserver-ip-10-0-0-1.eu-west-1.compute.internal",
+			"apiserver",
+		},
+		{
+			"controller-manager",
+			"kube-controller-manager-control-plane",
+			"controller-manager",
+		},
+		{
+			"scheduler",
+			"kube-scheduler-node1",
+			"scheduler",
+		},
+		{
+			"etcd",
+			"etcd-node1",
+			"etcd",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", ConfigSourceEnv).Return("file", true).Once()
+			utils.On("lookupEnv", PodNameEnv).Return(table.pod, true).Once()
+
+			controlPlaneResourceDetector := resourceDetector{utils: utils}
+
+			r, err := controlPlaneResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SPodName(table.pod),
+				componentKey.String(table.component),
+			), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotControlPlane(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", ConfigSourceEnv).Return("file", true).Once()
+	utils.On("lookupEnv", PodNameEnv).Return("coredns-5d78c9869d-abcde", true).Once()
+
+	controlPlaneResourceDetector := resourceDetector{utils: utils}
+
+	r, err := controlPlaneResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotStaticPod(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name   string
+		source string
+	}{
+		{
+			"unset",
+			"",
+		},
+		{
+			"api",
+			"api",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", ConfigSourceEnv).Return(table.source, table.source != "").Once()
+
+			controlPlaneResourceDetector := resourceDetector{utils: utils}
+
+			r, err := controlPlaneResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.Empty(), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
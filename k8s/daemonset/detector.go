@@ -0,0 +1,94 @@
+// Package daemonset provides an OpenTelemetry detector for detecting
+// Kubernetes DaemonSet pod resources.
+package daemonset
+
+import (
+	"context"
+	"os"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	// DaemonSetNameEnv is the environment variable that can be populated
+	// with the DaemonSet name. There's no downward API field for this so it
+	// has to be set explicitly, for example from a pod label.
+	DaemonSetNameEnv = "DAEMONSET_NAME"
+
+	// PodNameEnv is the environment variable that should be populated with
+	// the pod name using the downward API, i.e. from the `metadata.name`
+	// field.
+	PodNameEnv = "POD_NAME"
+
+	// NodeNameEnv is the environment variable that should be populated with
+	// the node name using the downward API, i.e. from the `spec.nodeName`
+	// field.
+	NodeNameEnv = "NODE_NAME"
+)
+
+// DaemonSet pods are named after the DaemonSet with a random five character
+// suffix appended, using the same alphabet as other generated names.
+var podNameRegexp = regexp.MustCompile(`^(.+)-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+
+// Deployment pods are named the same way after their ReplicaSet, which is
+// itself named after the Deployment with the pod template hash appended.
+var replicaSetNameRegexp = regexp.MustCompile(`-[bcdfghjklmnpqrstvwxz2456789]{6,10}$`)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type daemonSetDetectorUtils struct{}
+
+func (utils *daemonSetDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	attributes := make([]attribute.KeyValue, 0, 3)
+
+	podName, _ := detector.utils.lookupEnv(PodNameEnv)
+	if podName != "" {
+		attributes = append(attributes, semconv.K8SPodName(podName))
+	}
+
+	name, _ := detector.utils.lookupEnv(DaemonSetNameEnv)
+	if name == "" {
+		if m := podNameRegexp.FindStringSubmatch(podName); m != nil && !replicaSetNameRegexp.MatchString(m[1]) {
+			name = m[1]
+		}
+	}
+
+	if name == "" {
+		// Not a DaemonSet pod
+		return resource.Empty(), nil
+	}
+
+	attributes = append(attributes, semconv.K8SDaemonSetName(name))
+
+	if v, _ := detector.utils.lookupEnv(NodeNameEnv); v != "" {
+		attributes = append(attributes, semconv.K8SNodeName(v))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// Kubernetes DaemonSet pod resources. If [DaemonSetNameEnv] isn't set then
+// the DaemonSet is inferred from the pod name. Pods that look like they belong
+// to a Deployment's ReplicaSet are ignored, but a pod created some other way
+// with a similar name may still be mistaken for a DaemonSet pod.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(daemonSetDetectorUtils),
+	}
+}
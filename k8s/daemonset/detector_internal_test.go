@@ -0,0 +1,101 @@
+package daemonset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestDaemonSet(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name          string
+		podName       string
+		daemonSetName string
+		nodeName      string
+		expected      *resource.Resource
+	}{
+		{
+			name:          "env",
+			podName:       "fluent-bit-x7k2p",
+			daemonSetName: "fluent-bit",
+			nodeName:      "ip-10-0-1-23.eu-west-1.compute.internal",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SPodName("fluent-bit-x7k2p"),
+				semconv.K8SDaemonSetName("fluent-bit"),
+				semconv.K8SNodeName("ip-10-0-1-23.eu-west-1.compute.internal"),
+			),
+		},
+		{
+			name:          "env without pod name",
+			daemonSetName: "fluent-bit",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SDaemonSetName("fluent-bit"),
+			),
+		},
+		{
+			name:     "pod name",
+			podName:  "node-exporter-b4mzq",
+			nodeName: "worker-1",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SPodName("node-exporter-b4mzq"),
+				semconv.K8SDaemonSetName("node-exporter"),
+				semconv.K8SNodeName("worker-1"),
+			),
+		},
+		{
+			name:     "statefulset",
+			podName:  "web-0",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "deployment",
+			podName:  "web-7d4b9c8f6d-x2k9q",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "invalid suffix",
+			podName:  "web-aeiou",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "unset",
+			nodeName: "worker-1",
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", PodNameEnv).Return(table.podName, table.podName != "").Once()
+			utils.On("lookupEnv", DaemonSetNameEnv).Return(table.daemonSetName, table.daemonSetName != "").Once()
+			utils.On("lookupEnv", NodeNameEnv).Return(table.nodeName, table.nodeName != "").Maybe()
+
+			daemonSetResourceDetector := resourceDetector{utils: utils}
+
+			r, err := daemonSetResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Package job provides an OpenTelemetry detector for detecting Kubernetes Job
+// and CronJob resources.
+package job
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	// PodNameEnv is the environment variable that should be populated with
+	// the pod name using the downward API, i.e. from the `metadata.name`
+	// field.
+	PodNameEnv = "POD_NAME"
+
+	// JobNameEnv is the environment variable that should be populated with
+	// the name of the owning Job using the downward API, i.e. from the
+	// `metadata.labels['batch.kubernetes.io/job-name']` field. It is only
+	// non-empty for pods created by a Job.
+	JobNameEnv = "JOB_NAME"
+)
+
+// Jobs created by a CronJob are named after it with the scheduled time, in
+// minutes since the epoch, appended.
+var cronJobRegexp = regexp.MustCompile(`^(.+)-\d{8,}$`)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type jobDetectorUtils struct{}
+
+func (utils *jobDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	jobName, _ := detector.utils.lookupEnv(JobNameEnv)
+	if jobName == "" {
+		// Not a pod created by a Job
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.K8SJobName(jobName),
+	}
+
+	if m := cronJobRegexp.FindStringSubmatch(jobName); m != nil {
+		attributes = append(attributes, semconv.K8SCronJobName(m[1]))
+	}
+
+	// Pods are named after the Job with a random suffix appended
+	if v, _ := detector.utils.lookupEnv(PodNameEnv); strings.HasPrefix(v, jobName+"-") {
+		attributes = append(attributes, semconv.K8SPodName(v))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// Kubernetes Job and CronJob resources. The CronJob name is inferred from the
+// Job name so a Job created manually with a similar name will be mistaken for
+// one created by a CronJob.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(jobDetectorUtils),
+	}
+}
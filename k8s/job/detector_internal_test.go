@@ -0,0 +1,102 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestJob(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		jobName  string
+		podName  string
+		expected *resource.Resource
+	}{
+		{
+			name:    "job",
+			jobName: "db-migrate",
+			podName: "db-migrate-x7k2p",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SJobName("db-migrate"),
+				semconv.K8SPodName("db-migrate-x7k2p"),
+			),
+		},
+		{
+			name:    "cronjob",
+			jobName: "nightly-backup-28934520",
+			podName: "nightly-backup-28934520-4bq9z",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SJobName("nightly-backup-28934520"),
+				semconv.K8SCronJobName("nightly-backup"),
+				semconv.K8SPodName("nightly-backup-28934520-4bq9z"),
+			),
+		},
+		{
+			name:    "job with short numeric suffix",
+			jobName: "backup-2024",
+			podName: "backup-2024-abcde",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SJobName("backup-2024"),
+				semconv.K8SPodName("backup-2024-abcde"),
+			),
+		},
+		{
+			name:    "unrelated pod name",
+			jobName: "db-migrate",
+			podName: "web-7d4b9c8f6d-abcde",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SJobName("db-migrate"),
+			),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", JobNameEnv).Return(table.jobName, true).Once()
+			utils.On("lookupEnv", PodNameEnv).Return(table.podName, true).Once()
+
+			jobResourceDetector := resourceDetector{utils: utils}
+
+			r, err := jobResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotJob(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", JobNameEnv).Return("", false).Once()
+
+	jobResourceDetector := resourceDetector{utils: utils}
+
+	r, err := jobResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,225 @@
+// Package kubelet provides an OpenTelemetry detector for detecting Kubernetes
+// node resources using the kubelet API.
+package kubelet
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	// DefaultEndpoint is the default kubelet API endpoint.
+	DefaultEndpoint = "https://localhost:10250"
+
+	// ReadOnlyEndpoint is the kubelet read-only API endpoint, if it has been
+	// enabled.
+	ReadOnlyEndpoint = "http://localhost:10255"
+)
+
+var errUnavailable = errors.New("kubelet unavailable")
+
+type podList struct {
+	Items []struct {
+		Spec struct {
+			NodeName string `json:"nodeName"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// machineInfo is the subset of the cAdvisor machine information returned by
+// the kubelet.
+type machineInfo struct {
+	InstanceType string `json:"instance_type"` //nolint:tagliatelle
+	InstanceID   string `json:"instance_id"`   //nolint:tagliatelle
+}
+
+type detectorUtils interface {
+	get(ctx context.Context, path string, v any) error
+	closeIdleConnections()
+}
+
+type kubeletDetectorUtils struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func (utils *kubeletDetectorUtils) get(ctx context.Context, path string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.url+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	if utils.token != "" {
+		req.Header.Set("Authorization", "Bearer "+utils.token)
+	}
+
+	resp, err := utils.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// This includes being unauthorized or forbidden
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %q", errUnavailable, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (utils *kubeletDetectorUtils) closeIdleConnections() {
+	utils.client.CloseIdleConnections()
+}
+
+type resourceDetector struct {
+	utils     detectorUtils
+	endpoint  string
+	tlsConfig *tls.Config
+	token     string
+}
+
+func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	pods := new(podList)
+	if err := detector.utils.get(ctx, "/pods", pods); err != nil {
+		// No kubelet, or not allowed to query it
+		if errors.Is(err, errUnavailable) {
+			return resource.Empty(), nil
+		}
+
+		return nil, err
+	}
+
+	var attributes []attribute.KeyValue
+
+	// Every pod returned is running on this node
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			attributes = append(attributes, semconv.K8SNodeName(pod.Spec.NodeName))
+
+			break
+		}
+	}
+
+	// Newer kubelets no longer serve this endpoint
+	info := new(machineInfo)
+	if err := detector.utils.get(ctx, "/spec", info); err != nil && !errors.Is(err, errUnavailable) {
+		return nil, err
+	}
+
+	for _, s := range []struct {
+		value string
+		fn    func(string) attribute.KeyValue
+	}{
+		{
+			info.InstanceID,
+			semconv.HostID,
+		},
+		{
+			info.InstanceType,
+			semconv.HostType,
+		},
+	} {
+		if s.value != "" {
+			attributes = append(attributes, s.fn(s.value))
+		}
+	}
+
+	if len(attributes) == 0 {
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+// Close releases any idle connections to the kubelet API. It is safe to call
+// more than once.
+func (detector *resourceDetector) Close() error {
+	if detector.utils != nil {
+		detector.utils.closeIdleConnections()
+	}
+
+	return nil
+}
+
+var (
+	_ resource.Detector = new(resourceDetector)
+	_ io.Closer         = new(resourceDetector)
+)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithEndpoint sets the kubelet API endpoint. The default is
+// [DefaultEndpoint], use [ReadOnlyEndpoint] if the read-only port has been
+// enabled. The node IP, from the `status.hostIP` field using the downward API,
+// can be used if the pod isn't using the host network.
+func WithEndpoint(endpoint string) Option {
+	return func(detector *resourceDetector) {
+		detector.endpoint = endpoint
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the kubelet API.
+// The kubelet serving certificate is often self-signed so this can be used to
+// trust the right CA or, less securely, to skip verification. The default uses
+// the system roots.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(detector *resourceDetector) {
+		detector.tlsConfig = config
+	}
+}
+
+// WithBearerToken sets the bearer token sent to the kubelet API, such as the
+// pod's service account token. The service account needs permission to get
+// the `nodes/proxy` and `nodes/spec` subresources. The default is to send no
+// token, which only works with the read-only API.
+func WithBearerToken(token string) Option {
+	return func(detector *resourceDetector) {
+		detector.token = token
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// Kubernetes node resources using the kubelet API. The detector also
+// implements [io.Closer] and should be closed when the SDK is shut down.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		endpoint: DefaultEndpoint,
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = detector.tlsConfig
+
+	detector.utils = &kubeletDetectorUtils{
+		client: &http.Client{
+			Transport: transport,
+		},
+		url:   detector.endpoint,
+		token: detector.token,
+	}
+
+	return detector
+}
@@ -0,0 +1,207 @@
+package kubelet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	testToken = "token"
+
+	testPods = `{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "aws-node-abcde",
+        "namespace": "kube-system"
+      },
+      "spec": {
+        "nodeName": "ip-192-0-2-1.eu-west-1.compute.internal"
+      }
+    }
+  ]
+}`
+
+	testSpec = `{
+  "num_cores": 2,
+  "cloud_provider": "AWS",
+  "instance_type": "m5.large",
+  "instance_id": "i-0123456789abcdef0"
+}`
+)
+
+func newTestKubelet(spec bool) http.Handler {
+	mux := http.NewServeMux()
+
+	authorized := func(fn http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+testToken {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			fn(w, r)
+		}
+	}
+
+	mux.HandleFunc("GET /pods", authorized(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testPods))
+	}))
+
+	if spec {
+		mux.HandleFunc("GET /spec", authorized(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testSpec))
+		}))
+	}
+
+	return mux
+}
+
+func TestKubelet(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		spec     bool
+		token    string
+		expected *resource.Resource
+	}{
+		{
+			name:  "pods and spec",
+			spec:  true,
+			token: testToken,
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.K8SNodeName("ip-192-0-2-1.eu-west-1.compute.internal"),
+				semconv.HostID("i-0123456789abcdef0"),
+				semconv.HostType("m5.large"),
+			}...),
+		},
+		{
+			name:  "pods only",
+			token: testToken,
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.K8SNodeName("ip-192-0-2-1.eu-west-1.compute.internal"),
+			}...),
+		},
+		{
+			name:     "unauthorized",
+			spec:     true,
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(newTestKubelet(table.spec))
+			defer server.Close()
+
+			kubeletResourceDetector := resourceDetector{
+				utils: &kubeletDetectorUtils{
+					client: server.Client(),
+					url:    server.URL,
+					token:  table.token,
+				},
+			}
+
+			r, err := kubeletResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	r, err := NewResourceDetector(WithEndpoint(server.URL)).Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(newTestKubelet(true))
+	defer server.Close()
+
+	// Without trusting the test certificate the kubelet is unreachable
+	r, err := NewResourceDetector(WithEndpoint(server.URL), WithBearerToken(testToken)).Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	r, err = NewResourceDetector(
+		WithEndpoint(server.URL),
+		WithBearerToken(testToken),
+		WithTLSConfig(&tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}),
+	).Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.K8SNodeName("ip-192-0-2-1.eu-west-1.compute.internal"),
+		semconv.HostID("i-0123456789abcdef0"),
+		semconv.HostType("m5.large"),
+	}...), r)
+}
+
+type closeCountingTransport struct {
+	http.RoundTripper
+	closed atomic.Int32
+}
+
+func (transport *closeCountingTransport) CloseIdleConnections() {
+	transport.closed.Add(1)
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(newTestKubelet(true))
+	defer server.Close()
+
+	transport := &closeCountingTransport{
+		RoundTripper: server.Client().Transport,
+	}
+
+	kubeletResourceDetector := resourceDetector{
+		utils: &kubeletDetectorUtils{
+			client: &http.Client{
+				Transport: transport,
+			},
+			url:   server.URL,
+			token: testToken,
+		},
+	}
+
+	_, err := kubeletResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+
+	require.NoError(t, kubeletResourceDetector.Close())
+	require.NoError(t, kubeletResourceDetector.Close())
+	assert.Equal(t, int32(2), transport.closed.Load())
+
+	// Nothing has been allocated
+	require.NoError(t, new(resourceDetector).Close())
+}
@@ -0,0 +1,243 @@
+// Package limits provides an OpenTelemetry detector for detecting the CPU and
+// memory limits of a Kubernetes pod.
+package limits
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	// CPULimitEnv is the environment variable that can be populated with the
+	// CPU limit of the container, in cores, using the downward API, i.e. from
+	// the `limits.cpu` resource field. The default divisor of 1 rounds the
+	// limit up to a whole number of cores.
+	CPULimitEnv = "CPU_LIMIT"
+
+	// MemoryLimitEnv is the environment variable that can be populated with
+	// the memory limit of the container, in bytes, using the downward API,
+	// i.e. from the `limits.memory` resource field.
+	MemoryLimitEnv = "MEMORY_LIMIT"
+)
+
+const (
+	cpuLimitKey    = attribute.Key("k8s.pod.cpu_limit")
+	memoryLimitKey = attribute.Key("k8s.pod.memory_limit")
+)
+
+const (
+	cgroupV2CPUFile    = "sys/fs/cgroup/cpu.max"
+	cgroupV2MemoryFile = "sys/fs/cgroup/memory.max"
+
+	cgroupV1QuotaFile  = "sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1PeriodFile = "sys/fs/cgroup/cpu/cpu.cfs_period_us"
+	cgroupV1MemoryFile = "sys/fs/cgroup/memory/memory.limit_in_bytes"
+
+	// cgroup v1 reports an unlimited memory limit as the largest page-aligned
+	// value so anything this large is effectively unlimited.
+	unlimitedMemory = 1 << 62
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type limitsDetectorUtils struct{}
+
+func (utils *limitsDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+	fsys  fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	attributes := make([]attribute.KeyValue, 0, 2)
+
+	cpu, err := detector.cpuLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	if cpu > 0 {
+		attributes = append(attributes, cpuLimitKey.Float64(cpu))
+	}
+
+	memory, err := detector.memoryLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	if memory > 0 {
+		attributes = append(attributes, memoryLimitKey.Int64(memory))
+	}
+
+	if len(attributes) == 0 {
+		// No limits, or not in a container
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+func (detector *resourceDetector) cpuLimit() (float64, error) {
+	if v, _ := detector.utils.lookupEnv(CPULimitEnv); v != "" {
+		// A malformed value, such as a quantity like "500m" set directly
+		// rather than from the resource field, is skipped
+		cpu, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, nil //nolint:nilerr
+		}
+
+		return cpu, nil
+	}
+
+	// cgroup v2 has the quota and period in one file, such as "200000 100000"
+	b, ok, err := detector.readFile(cgroupV2CPUFile)
+	if err != nil {
+		return 0, err
+	}
+
+	if ok {
+		quota, period, _ := strings.Cut(string(b), " ")
+
+		return parseCPULimit(cgroupV2CPUFile, quota, period)
+	}
+
+	quota, ok, err := detector.readFile(cgroupV1QuotaFile)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	period, ok, err := detector.readFile(cgroupV1PeriodFile)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	return parseCPULimit(cgroupV1QuotaFile, string(quota), string(period))
+}
+
+func parseCPULimit(file, quota, period string) (float64, error) {
+	// An unlimited quota is "max" with cgroup v2 and "-1" with cgroup v1
+	if quota == "max" || quota == "-1" {
+		return 0, nil
+	}
+
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %w", file, err)
+	}
+
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %w", file, err)
+	}
+
+	if q <= 0 || p <= 0 {
+		return 0, nil
+	}
+
+	return float64(q) / float64(p), nil
+}
+
+func (detector *resourceDetector) memoryLimit() (int64, error) {
+	if v, _ := detector.utils.lookupEnv(MemoryLimitEnv); v != "" {
+		// A malformed value, such as a quantity like "512Mi" set directly
+		// rather than from the resource field, is skipped
+		memory, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, nil //nolint:nilerr
+		}
+
+		return memory, nil
+	}
+
+	for _, file := range []string{cgroupV2MemoryFile, cgroupV1MemoryFile} {
+		b, ok, err := detector.readFile(file)
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if string(b) == "max" {
+			return 0, nil
+		}
+
+		memory, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing %s: %w", file, err)
+		}
+
+		if memory >= unlimitedMemory {
+			return 0, nil
+		}
+
+		return memory, nil
+	}
+
+	return 0, nil
+}
+
+func (detector *resourceDetector) readFile(file string) ([]byte, bool, error) {
+	b, err := fs.ReadFile(detector.fsys, file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("error reading %s: %w", file, err)
+	}
+
+	return bytes.TrimSpace(b), true, nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read the cgroup limits under
+// /sys/fs/cgroup. It should be rooted at "/". The default is the real OS
+// filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+func newResourceDetector(utils detectorUtils, options ...Option) *resourceDetector {
+	detector := &resourceDetector{
+		utils: utils,
+		fsys:  os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect the CPU
+// and memory limits of a Kubernetes pod. The limits are read from
+// [CPULimitEnv] and [MemoryLimitEnv] if set, otherwise from the cgroup of the
+// container. A limit is omitted if its environment variable can't be parsed.
+func NewResourceDetector(options ...Option) resource.Detector {
+	return newResourceDetector(new(limitsDetectorUtils), options...)
+}
@@ -0,0 +1,140 @@
+package limits
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestLimits(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		cpu      string
+		memory   string
+		fsys     fstest.MapFS
+		expected *resource.Resource
+	}{
+		{
+			name:   "env",
+			cpu:    "2",
+			memory: "536870912",
+			fsys: fstest.MapFS{
+				"sys/fs/cgroup/cpu.max":    &fstest.MapFile{Data: []byte("50000 100000\n")},
+				"sys/fs/cgroup/memory.max": &fstest.MapFile{Data: []byte("268435456\n")},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				cpuLimitKey.Float64(2),
+				memoryLimitKey.Int64(536870912),
+			),
+		},
+		{
+			name:   "malformed env",
+			cpu:    "2",
+			memory: "512Mi",
+			fsys: fstest.MapFS{
+				"sys/fs/cgroup/cpu.max":    &fstest.MapFile{Data: []byte("50000 100000\n")},
+				"sys/fs/cgroup/memory.max": &fstest.MapFile{Data: []byte("268435456\n")},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				cpuLimitKey.Float64(2),
+			),
+		},
+		{
+			name: "cgroup v2",
+			fsys: fstest.MapFS{
+				"sys/fs/cgroup/cpu.max":    &fstest.MapFile{Data: []byte("50000 100000\n")},
+				"sys/fs/cgroup/memory.max": &fstest.MapFile{Data: []byte("268435456\n")},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				cpuLimitKey.Float64(0.5),
+				memoryLimitKey.Int64(268435456),
+			),
+		},
+		{
+			name: "cgroup v2 unlimited",
+			fsys: fstest.MapFS{
+				"sys/fs/cgroup/cpu.max":    &fstest.MapFile{Data: []byte("max 100000\n")},
+				"sys/fs/cgroup/memory.max": &fstest.MapFile{Data: []byte("max\n")},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name: "cgroup v1",
+			fsys: fstest.MapFS{
+				"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         &fstest.MapFile{Data: []byte("150000\n")},
+				"sys/fs/cgroup/cpu/cpu.cfs_period_us":        &fstest.MapFile{Data: []byte("100000\n")},
+				"sys/fs/cgroup/memory/memory.limit_in_bytes": &fstest.MapFile{Data: []byte("1073741824\n")},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				cpuLimitKey.Float64(1.5),
+				memoryLimitKey.Int64(1073741824),
+			),
+		},
+		{
+			name: "cgroup v1 unlimited",
+			fsys: fstest.MapFS{
+				"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         &fstest.MapFile{Data: []byte("-1\n")},
+				"sys/fs/cgroup/cpu/cpu.cfs_period_us":        &fstest.MapFile{Data: []byte("100000\n")},
+				"sys/fs/cgroup/memory/memory.limit_in_bytes": &fstest.MapFile{Data: []byte("9223372036854771712\n")},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name:     "no limits",
+			fsys:     fstest.MapFS{},
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", CPULimitEnv).Return(table.cpu, table.cpu != "").Once()
+			utils.On("lookupEnv", MemoryLimitEnv).Return(table.memory, table.memory != "").Once()
+
+			limitsResourceDetector := newResourceDetector(utils, WithFS(table.fsys))
+
+			r, err := limitsResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", CPULimitEnv).Return("", false).Once()
+
+	limitsResourceDetector := newResourceDetector(utils, WithFS(fstest.MapFS{
+		"sys/fs/cgroup/cpu.max": &fstest.MapFile{Data: []byte("lots 100000\n")},
+	}))
+
+	r, err := limitsResourceDetector.Detect(t.Context())
+	require.Error(t, err)
+	assert.Nil(t, r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,122 @@
+// Package serviceaccount provides an OpenTelemetry detector for detecting
+// Kubernetes cluster resources from the issuer of the service account token.
+package serviceaccount
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const tokenFile = "var/run/secrets/kubernetes.io/serviceaccount/token"
+
+const (
+	oidcIssuerKey   = attribute.Key("k8s.cluster.oidc_issuer")
+	eksClusterIDKey = attribute.Key("aws.eks.cluster.id")
+)
+
+var errMalformed = errors.New("malformed token")
+
+// EKS issuers are per-cluster, such as
+// "https://oidc.eks.eu-west-1.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE".
+var eksIssuerRegexp = regexp.MustCompile(`^https://oidc\.eks\.[^/]+\.amazonaws\.com(?:\.cn)?/id/([0-9A-Za-z]+)$`)
+
+// claims is the subset of the token claims that are used.
+type claims struct {
+	Issuer string `json:"iss"`
+}
+
+type resourceDetector struct {
+	fsys fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	b, err := fs.ReadFile(detector.fsys, tokenFile)
+	if err != nil {
+		// Not in a Kubernetes pod, or the token isn't mounted
+		if errors.Is(err, fs.ErrNotExist) {
+			return resource.Empty(), nil
+		}
+
+		return nil, fmt.Errorf("error reading %s: %w", tokenFile, err)
+	}
+
+	// The signature isn't verified, the token is only used for its claims
+	c, err := parseClaims(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Issuer == "" {
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		oidcIssuerKey.String(c.Issuer),
+	}
+
+	if m := eksIssuerRegexp.FindStringSubmatch(c.Issuer); m != nil {
+		attributes = append(attributes, eksClusterIDKey.String(m[1]))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+func parseClaims(token string) (*claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformed
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errMalformed, err)
+	}
+
+	c := new(claims)
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%w: %w", errMalformed, err)
+	}
+
+	return c, nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read the service account token. It
+// should be rooted at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect the OIDC
+// issuer of the Kubernetes cluster from the service account token, and for
+// EKS the cluster ID. The token signature isn't verified.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		fsys: os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
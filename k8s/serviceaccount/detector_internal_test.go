@@ -0,0 +1,120 @@
+package serviceaccount
+
+import (
+	"encoding/base64"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// newToken returns an unsigned JWT with the given JSON claims.
+func newToken(claims string) []byte {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"test"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	return []byte(header + "." + payload + ".c2lnbmF0dXJl")
+}
+
+func TestServiceAccount(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected *resource.Resource
+	}{
+		{
+			name: "eks",
+			fsys: fstest.MapFS{
+				"var/run/secrets/kubernetes.io/serviceaccount/token": &fstest.MapFile{
+					Data: newToken(`{"aud":["sts.amazonaws.com"],` +
+						`"iss":"https://oidc.eks.eu-west-1.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE",` +
+						`"sub":"system:serviceaccount:default:app"}`),
+				},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				oidcIssuerKey.String("https://oidc.eks.eu-west-1.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"),
+				eksClusterIDKey.String("EXAMPLED539D4633E53DE1B71EXAMPLE"),
+			),
+		},
+		{
+			name: "self-managed",
+			fsys: fstest.MapFS{
+				"var/run/secrets/kubernetes.io/serviceaccount/token": &fstest.MapFile{
+					Data: newToken(`{"aud":["https://kubernetes.default.svc.cluster.local"],` +
+						`"iss":"https://kubernetes.default.svc.cluster.local",` +
+						`"sub":"system:serviceaccount:default:app"}`),
+				},
+			},
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				oidcIssuerKey.String("https://kubernetes.default.svc.cluster.local"),
+			),
+		},
+		{
+			name: "no issuer",
+			fsys: fstest.MapFS{
+				"var/run/secrets/kubernetes.io/serviceaccount/token": &fstest.MapFile{
+					Data: newToken(`{"sub":"system:serviceaccount:default:app"}`),
+				},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name:     "no token",
+			fsys:     fstest.MapFS{},
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			serviceAccountResourceDetector := NewResourceDetector(WithFS(table.fsys))
+
+			r, err := serviceAccountResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name  string
+		token []byte
+	}{
+		{
+			name:  "not a jwt",
+			token: []byte("not-a-token"),
+		},
+		{
+			name:  "bad encoding",
+			token: []byte("eyJhbGciOiJSUzI1NiJ9.!!!.c2lnbmF0dXJl"),
+		},
+		{
+			name:  "bad json",
+			token: newToken(`{"iss":`),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			serviceAccountResourceDetector := NewResourceDetector(WithFS(fstest.MapFS{
+				"var/run/secrets/kubernetes.io/serviceaccount/token": &fstest.MapFile{Data: table.token},
+			}))
+
+			r, err := serviceAccountResourceDetector.Detect(t.Context())
+			require.ErrorIs(t, err, errMalformed)
+			assert.Nil(t, r)
+		})
+	}
+}
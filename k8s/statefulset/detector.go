@@ -0,0 +1,72 @@
+// Package statefulset provides an OpenTelemetry detector for detecting
+// Kubernetes StatefulSet pod resources.
+package statefulset
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// PodNameEnv is the environment variable that should be populated with the
+// pod name using the downward API, i.e. from the `metadata.name` field.
+const PodNameEnv = "POD_NAME"
+
+const podOrdinalKey = attribute.Key("k8s.pod.ordinal")
+
+// StatefulSet pods are named after the StatefulSet with the ordinal appended,
+// which never has a leading zero.
+var podNameRegexp = regexp.MustCompile(`^(.+)-(0|[1-9]\d*)$`)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type statefulSetDetectorUtils struct{}
+
+func (utils *statefulSetDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	name, _ := detector.utils.lookupEnv(PodNameEnv)
+
+	m := podNameRegexp.FindStringSubmatch(name)
+	if m == nil {
+		// Not a StatefulSet pod
+		return resource.Empty(), nil
+	}
+
+	ordinal, err := strconv.Atoi(m[2])
+	if err != nil {
+		// Too large to be an ordinal
+		return resource.Empty(), nil //nolint:nilerr
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.K8SPodName(name),
+		semconv.K8SStatefulSetName(m[1]),
+		podOrdinalKey.Int(ordinal),
+	), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect
+// Kubernetes StatefulSet pod resources. The StatefulSet is inferred from the
+// pod name so a pod created some other way with a similar name will be
+// mistaken for a StatefulSet pod.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(statefulSetDetectorUtils),
+	}
+}
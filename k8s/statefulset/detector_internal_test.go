@@ -0,0 +1,91 @@
+package statefulset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestStatefulSet(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		podName  string
+		expected *resource.Resource
+	}{
+		{
+			name:    "first",
+			podName: "web-0",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SPodName("web-0"),
+				semconv.K8SStatefulSetName("web"),
+				podOrdinalKey.Int(0),
+			),
+		},
+		{
+			name:    "hyphenated",
+			podName: "kafka-broker-12",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.K8SPodName("kafka-broker-12"),
+				semconv.K8SStatefulSetName("kafka-broker"),
+				podOrdinalKey.Int(12),
+			),
+		},
+		{
+			name:     "deployment",
+			podName:  "web-7d4b9c8f6d-x7k2p",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "leading zero",
+			podName:  "web-01",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "no ordinal",
+			podName:  "web",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "overflow",
+			podName:  "web-99999999999999999999",
+			expected: resource.Empty(),
+		},
+		{
+			name:     "unset",
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", PodNameEnv).Return(table.podName, table.podName != "").Once()
+
+			statefulSetResourceDetector := resourceDetector{utils: utils}
+
+			r, err := statefulSetResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
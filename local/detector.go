@@ -0,0 +1,75 @@
+// Package local provides an OpenTelemetry detector for detecting local
+// development environments, such as those run with Tilt or Docker Compose.
+package local
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	tiltHostEnv           = "TILT_HOST"
+	composeProjectNameEnv = "COMPOSE_PROJECT_NAME"
+)
+
+const (
+	toolKey               = attribute.Key("local.tool")
+	composeProjectNameKey = attribute.Key("docker.compose.project")
+)
+
+const development = "development"
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type localDetectorUtils struct{}
+
+func (utils *localDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	attributes := make([]attribute.KeyValue, 0, 3)
+
+	if v, _ := detector.utils.lookupEnv(tiltHostEnv); v != "" {
+		attributes = append(attributes, toolKey.String("tilt"))
+	}
+
+	if v, _ := detector.utils.lookupEnv(composeProjectNameEnv); v != "" {
+		// Tilt can also run Docker Compose projects
+		if len(attributes) == 0 {
+			attributes = append(attributes, toolKey.String("docker-compose"))
+		}
+
+		attributes = append(attributes, composeProjectNameKey.String(v))
+	}
+
+	if len(attributes) == 0 {
+		// Not a local development environment
+		return resource.Empty(), nil
+	}
+
+	attributes = append(attributes, semconv.DeploymentEnvironmentNameKey.String(development))
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect local
+// development environments. The deployment environment is always
+// "development".
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(localDetectorUtils),
+	}
+}
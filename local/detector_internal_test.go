@@ -0,0 +1,82 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestLocal(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		tiltHost string
+		project  string
+		expected *resource.Resource
+	}{
+		{
+			name:     "tilt",
+			tiltHost: "localhost",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				toolKey.String("tilt"),
+				semconv.DeploymentEnvironmentNameKey.String("development"),
+			),
+		},
+		{
+			name:    "docker compose",
+			project: "myapp",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				toolKey.String("docker-compose"),
+				composeProjectNameKey.String("myapp"),
+				semconv.DeploymentEnvironmentNameKey.String("development"),
+			),
+		},
+		{
+			name:     "tilt with docker compose",
+			tiltHost: "localhost",
+			project:  "myapp",
+			expected: resource.NewWithAttributes(semconv.SchemaURL,
+				toolKey.String("tilt"),
+				composeProjectNameKey.String("myapp"),
+				semconv.DeploymentEnvironmentNameKey.String("development"),
+			),
+		},
+		{
+			name:     "not local",
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", tiltHostEnv).Return(table.tiltHost, table.tiltHost != "").Once()
+			utils.On("lookupEnv", composeProjectNameEnv).Return(table.project, table.project != "").Once()
+
+			localResourceDetector := resourceDetector{utils: utils}
+
+			r, err := localResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
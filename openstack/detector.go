@@ -0,0 +1,181 @@
+// Package openstack provides an OpenTelemetry detector for detecting OpenStack
+// instance resources.
+package openstack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const metadataURL = "http://169.254.169.254/openstack/latest/meta_data.json"
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = 100 * time.Millisecond
+)
+
+var (
+	errUnavailable = errors.New("metadata unavailable")
+	errTransient   = errors.New("transient error")
+)
+
+type metadata struct {
+	UUID             string `json:"uuid"`
+	Name             string `json:"name"`
+	Hostname         string `json:"hostname"`
+	AvailabilityZone string `json:"availability_zone"` //nolint:tagliatelle
+	ProjectID        string `json:"project_id"`        //nolint:tagliatelle
+}
+
+type detectorUtils interface {
+	getMetadata(ctx context.Context) (*metadata, error)
+}
+
+type openStackDetectorUtils struct {
+	client   *http.Client
+	url      string
+	attempts int
+	backoff  time.Duration
+}
+
+// getMetadata fetches meta_data.json, retrying if the Nova metadata API
+// returns a server error, for example while the instance is still being
+// provisioned.
+func (utils *openStackDetectorUtils) getMetadata(ctx context.Context) (*metadata, error) {
+	for attempt := 1; ; attempt++ {
+		m, err := utils.fetchMetadata(ctx)
+		if err == nil || !errors.Is(err, errTransient) || attempt >= utils.attempts {
+			return m, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(utils.backoff):
+		}
+	}
+}
+
+func (utils *openStackDetectorUtils) fetchMetadata(ctx context.Context) (*metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := utils.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// The metadata service may not be ready yet
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: %w: unexpected status %q", errUnavailable, errTransient, resp.Status)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %q", errUnavailable, resp.Status)
+	}
+
+	m := new(metadata)
+	if err := json.NewDecoder(resp.Body).Decode(m); err != nil {
+		return nil, fmt.Errorf("error decoding metadata: %w", err)
+	}
+
+	return m, nil
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	m, err := detector.utils.getMetadata(ctx)
+	if err != nil {
+		// Not on OpenStack, or the metadata service is unreachable
+		if errors.Is(err, errUnavailable) {
+			return resource.Empty(), nil
+		}
+
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CloudProviderKey.String("openstack"),
+	}
+
+	// The metadata doesn't include the region, only the availability zone
+	for _, s := range []struct {
+		value string
+		fn    func(string) attribute.KeyValue
+	}{
+		{
+			m.AvailabilityZone,
+			semconv.CloudAvailabilityZone,
+		},
+		{
+			m.ProjectID,
+			semconv.CloudAccountID,
+		},
+		{
+			m.UUID,
+			semconv.HostID,
+		},
+		{
+			m.Hostname,
+			semconv.HostName,
+		},
+	} {
+		if s.value != "" {
+			attributes = append(attributes, s.fn(s.value))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*openStackDetectorUtils)
+
+// WithMetadataRetries configures retrying of the OpenStack metadata API when
+// it responds with a server error. The default is 3 attempts with a backoff of
+// 100ms; an unreachable metadata API is never retried.
+func WithMetadataRetries(attempts int, backoff time.Duration) Option {
+	return func(utils *openStackDetectorUtils) {
+		utils.attempts = attempts
+		utils.backoff = backoff
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect OpenStack
+// instance resources.
+func NewResourceDetector(options ...Option) resource.Detector {
+	utils := &openStackDetectorUtils{
+		client:   http.DefaultClient,
+		url:      metadataURL,
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+
+	for _, option := range options {
+		option(utils)
+	}
+
+	return &resourceDetector{
+		utils: utils,
+	}
+}
@@ -0,0 +1,143 @@
+package openstack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+func TestOpenStack(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/openstack/latest/meta_data.json", r.URL.Path)
+
+		http.ServeFile(w, r, "testdata/meta_data.json")
+	}))
+	defer server.Close()
+
+	openStackResourceDetector := resourceDetector{
+		utils: &openStackDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/openstack/latest/meta_data.json",
+		},
+	}
+
+	r, err := openStackResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CloudProviderKey.String("openstack"),
+		semconv.CloudAvailabilityZone("nova"),
+		semconv.CloudAccountID("f7ac731cc11f40efbc03a9f9e1d1d21f"),
+		semconv.HostID("d8e02d56-2648-49a3-bf97-6be8f1204f38"),
+		semconv.HostName("web-1.novalocal"),
+	}...), r)
+}
+
+func TestNotOpenStack(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	openStackResourceDetector := resourceDetector{
+		utils: &openStackDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/openstack/latest/meta_data.json",
+		},
+	}
+
+	r, err := openStackResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	openStackResourceDetector := resourceDetector{
+		utils: &openStackDetectorUtils{
+			client: server.Client(),
+			url:    server.URL + "/openstack/latest/meta_data.json",
+		},
+	}
+
+	r, err := openStackResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+}
+
+func TestMetadataRetries(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name     string
+		attempts int
+		expected *resource.Resource
+	}{
+		{
+			name:     "retried",
+			attempts: 3,
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.CloudProviderKey.String("openstack"),
+				semconv.CloudAvailabilityZone("nova"),
+				semconv.CloudAccountID("f7ac731cc11f40efbc03a9f9e1d1d21f"),
+				semconv.HostID("d8e02d56-2648-49a3-bf97-6be8f1204f38"),
+				semconv.HostName("web-1.novalocal"),
+			}...),
+		},
+		{
+			name:     "too few attempts",
+			attempts: 2,
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeFile(w, r, "testdata/meta_data.json")
+			})
+
+			// The metadata service fails twice before it's ready
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			openStackResourceDetector := NewResourceDetector(
+				WithMetadataRetries(table.attempts, time.Millisecond),
+				func(utils *openStackDetectorUtils) {
+					utils.client = server.Client()
+					utils.url = server.URL + "/openstack/latest/meta_data.json"
+				},
+			)
+
+			r, err := openStackResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+			assert.Equal(t, int32(table.attempts), requests.Load())
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package process provides an OpenTelemetry detector for detecting attributes
+// of the current process.
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type detectorUtils interface {
+	pid() int
+	executable() (string, error)
+	args() []string
+	runtimeVersion() string
+}
+
+type processDetectorUtils struct{}
+
+func (utils *processDetectorUtils) pid() int {
+	return os.Getpid()
+}
+
+func (utils *processDetectorUtils) executable() (string, error) {
+	return os.Executable()
+}
+
+func (utils *processDetectorUtils) args() []string {
+	return os.Args
+}
+
+func (utils *processDetectorUtils) runtimeVersion() string {
+	return runtime.Version()
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	args := detector.utils.args()
+
+	attributes := []attribute.KeyValue{
+		semconv.ProcessPID(detector.utils.pid()),
+		semconv.ProcessRuntimeName("go"),
+		semconv.ProcessRuntimeVersion(detector.utils.runtimeVersion()),
+	}
+
+	executable, err := detector.utils.executable()
+	if err != nil {
+		// Fall back to the name the process was invoked as
+		if len(args) == 0 {
+			return nil, fmt.Errorf("error finding executable: %w", err)
+		}
+
+		executable = args[0]
+	}
+
+	attributes = append(attributes, semconv.ProcessExecutableName(filepath.Base(executable)))
+
+	if len(args) > 0 {
+		attributes = append(attributes, semconv.ProcessCommandArgs(args...))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect the
+// process ID, executable name, command arguments, and Go runtime of the
+// current process.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(processDetectorUtils),
+	}
+}
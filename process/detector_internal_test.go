@@ -0,0 +1,124 @@
+//nolint:forcetypeassert
+package process
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+var errTest = errors.New("test")
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) pid() int {
+	args := utils.Called()
+
+	return args.Int(0)
+}
+
+func (utils *mockDetectorUtils) executable() (string, error) {
+	args := utils.Called()
+
+	return args.String(0), args.Error(1)
+}
+
+func (utils *mockDetectorUtils) args() []string {
+	args := utils.Called()
+
+	if v := args.Get(0); v != nil {
+		return v.([]string)
+	}
+
+	return nil
+}
+
+func (utils *mockDetectorUtils) runtimeVersion() string {
+	args := utils.Called()
+
+	return args.String(0)
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name          string
+		executable    string
+		executableErr error
+		args          []string
+		expected      []attribute.KeyValue
+		err           error
+	}{
+		{
+			name:       "executable",
+			executable: "/usr/local/bin/server",
+			args:       []string{"./server", "-v"},
+			expected: []attribute.KeyValue{
+				semconv.ProcessPID(1234),
+				semconv.ProcessRuntimeName("go"),
+				semconv.ProcessRuntimeVersion("go1.25.0"),
+				semconv.ProcessExecutableName("server"),
+				semconv.ProcessCommandArgs("./server", "-v"),
+			},
+		},
+		{
+			name:          "args fallback",
+			executableErr: errTest,
+			args:          []string{"/opt/app/bin/worker"},
+			expected: []attribute.KeyValue{
+				semconv.ProcessPID(1234),
+				semconv.ProcessRuntimeName("go"),
+				semconv.ProcessRuntimeVersion("go1.25.0"),
+				semconv.ProcessExecutableName("worker"),
+				semconv.ProcessCommandArgs("/opt/app/bin/worker"),
+			},
+		},
+		{
+			name:          "error",
+			executableErr: errTest,
+			err:           errTest,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("pid").Return(1234).Once()
+			utils.On("executable").Return(table.executable, table.executableErr).Once()
+			utils.On("args").Return(table.args).Once()
+			utils.On("runtimeVersion").Return("go1.25.0").Once()
+
+			processResourceDetector := resourceDetector{utils: utils}
+
+			r, err := processResourceDetector.Detect(t.Context())
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+				assert.Nil(t, r)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, table.expected...), r)
+			}
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLiveProcess(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewResourceDetector().Detect(t.Context())
+	require.NoError(t, err)
+	assert.NotEqual(t, resource.Empty(), r)
+}
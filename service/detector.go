@@ -0,0 +1,131 @@
+// Package service provides an OpenTelemetry detector for detecting service
+// identity from the standard OpenTelemetry environment variables.
+//
+// The SDK already merges these environment variables into the default
+// resource, this detector is for when only the validated service attributes
+// are wanted, for example when composing resources from explicit detectors.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	serviceNameEnv        = "OTEL_SERVICE_NAME"
+	resourceAttributesEnv = "OTEL_RESOURCE_ATTRIBUTES"
+)
+
+var errMalformed = errors.New("malformed attribute")
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type serviceDetectorUtils struct{}
+
+func (utils *serviceDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	values := make(map[attribute.Key]string)
+
+	if v, _ := detector.utils.lookupEnv(resourceAttributesEnv); v != "" {
+		m, err := parseResourceAttributes(v)
+		if err != nil {
+			return nil, err
+		}
+
+		values = m
+	}
+
+	// This takes precedence over any "service.name" attribute
+	if v, _ := detector.utils.lookupEnv(serviceNameEnv); strings.TrimSpace(v) != "" {
+		values[semconv.ServiceNameKey] = strings.TrimSpace(v)
+	}
+
+	var attributes []attribute.KeyValue
+
+	for _, s := range []struct {
+		key attribute.Key
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			semconv.ServiceNameKey,
+			semconv.ServiceName,
+		},
+		{
+			semconv.ServiceVersionKey,
+			semconv.ServiceVersion,
+		},
+		{
+			semconv.ServiceInstanceIDKey,
+			semconv.ServiceInstanceID,
+		},
+	} {
+		if v := values[s.key]; v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	if len(attributes) == 0 {
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// parseResourceAttributes parses a comma-separated list of "key=value" pairs
+// with percent-encoded values, returning only the "service." keys.
+func parseResourceAttributes(s string) (map[attribute.Key]string, error) {
+	m := make(map[attribute.Key]string)
+
+	for pair := range strings.SplitSeq(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%w: %q", errMalformed, pair)
+		}
+
+		key = strings.TrimSpace(key)
+		if !strings.HasPrefix(key, "service.") {
+			continue
+		}
+
+		v, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", errMalformed, pair, err)
+		}
+
+		m[attribute.Key(key)] = v
+	}
+
+	return m, nil
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect the
+// service name, version, and instance ID from the `OTEL_SERVICE_NAME` and
+// `OTEL_RESOURCE_ATTRIBUTES` environment variables.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(serviceDetectorUtils),
+	}
+}
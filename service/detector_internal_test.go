@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name               string
+		serviceName        string
+		resourceAttributes string
+		expected           *resource.Resource
+		err                error
+	}{
+		{
+			name:               "resource attributes",
+			resourceAttributes: "service.name=checkout,service.version=1.2.3,service.instance.id=627cc493,deployment.environment.name=prod",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.ServiceName("checkout"),
+				semconv.ServiceVersion("1.2.3"),
+				semconv.ServiceInstanceID("627cc493"),
+			}...),
+		},
+		{
+			name:               "service name precedence",
+			serviceName:        "payments",
+			resourceAttributes: "service.name=checkout, service.version = 1.2.3",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.ServiceName("payments"),
+				semconv.ServiceVersion("1.2.3"),
+			}...),
+		},
+		{
+			name:        "service name only",
+			serviceName: "payments",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.ServiceName("payments"),
+			}...),
+		},
+		{
+			name:               "percent encoded",
+			resourceAttributes: "service.name=my%20service,",
+			expected: resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+				semconv.ServiceName("my service"),
+			}...),
+		},
+		{
+			name:               "no service attributes",
+			resourceAttributes: "host.name=example",
+			expected:           resource.Empty(),
+		},
+		{
+			name:     "unset",
+			expected: resource.Empty(),
+		},
+		{
+			name:               "missing value",
+			resourceAttributes: "service.name=checkout,service.version",
+			err:                errMalformed,
+		},
+		{
+			name:               "bad encoding",
+			resourceAttributes: "service.name=%zz",
+			err:                errMalformed,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", resourceAttributesEnv).Return(table.resourceAttributes, table.resourceAttributes != "").Once()
+			utils.On("lookupEnv", serviceNameEnv).Return(table.serviceName, table.serviceName != "").Maybe()
+
+			serviceResourceDetector := resourceDetector{utils: utils}
+
+			r, err := serviceResourceDetector.Detect(t.Context())
+			if table.err != nil {
+				require.ErrorIs(t, err, table.err)
+				assert.Nil(t, r)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, table.expected, r)
+			}
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
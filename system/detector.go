@@ -0,0 +1,158 @@
+// Package system provides an OpenTelemetry detector for detecting host and
+// operating system attributes that are always available regardless of cloud.
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+//nolint:gochecknoglobals
+var (
+	osReleaseFiles = []string{
+		"etc/os-release",
+		"usr/lib/os-release",
+	}
+
+	archs = map[string]attribute.KeyValue{
+		"386":     semconv.HostArchX86,
+		"amd64":   semconv.HostArchAMD64,
+		"arm":     semconv.HostArchARM32,
+		"arm64":   semconv.HostArchARM64,
+		"ppc64":   semconv.HostArchPPC64,
+		"ppc64le": semconv.HostArchPPC64,
+		"s390x":   semconv.HostArchS390x,
+	}
+
+	osTypes = map[string]attribute.KeyValue{
+		"aix":       semconv.OSTypeAIX,
+		"darwin":    semconv.OSTypeDarwin,
+		"dragonfly": semconv.OSTypeDragonflyBSD,
+		"freebsd":   semconv.OSTypeFreeBSD,
+		"linux":     semconv.OSTypeLinux,
+		"netbsd":    semconv.OSTypeNetBSD,
+		"openbsd":   semconv.OSTypeOpenBSD,
+		"solaris":   semconv.OSTypeSolaris,
+		"windows":   semconv.OSTypeWindows,
+		"zos":       semconv.OSTypeZOS,
+	}
+)
+
+type resourceDetector struct {
+	goos   string
+	goarch string
+	fsys   fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	attributes := make([]attribute.KeyValue, 0, 3)
+
+	if kv, ok := archs[detector.goarch]; ok {
+		attributes = append(attributes, kv)
+	}
+
+	if kv, ok := osTypes[detector.goos]; ok {
+		attributes = append(attributes, kv)
+	}
+
+	name, err := detector.osName()
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		attributes = append(attributes, semconv.OSName(name))
+	}
+
+	if len(attributes) == 0 {
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+func (detector *resourceDetector) osName() (string, error) {
+	for _, file := range osReleaseFiles {
+		b, err := fs.ReadFile(detector.fsys, file)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return "", fmt.Errorf("error reading %s: %w", file, err)
+		}
+
+		return parseOSRelease(b)["NAME"], nil
+	}
+
+	return "", nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+func parseOSRelease(b []byte) map[string]string {
+	m := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		if v, err := strconv.Unquote(value); err == nil {
+			value = v
+		} else {
+			value = strings.Trim(value, `'`)
+		}
+
+		m[key] = value
+	}
+
+	return m
+}
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read os-release(5). It should be rooted
+// at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect the host
+// architecture, operating system type, and operating system name.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		goos:   runtime.GOOS,
+		goarch: runtime.GOARCH,
+		fsys:   os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+func TestSystem(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("testdata/os-release")
+	require.NoError(t, err)
+
+	tables := []struct {
+		name     string
+		goos     string
+		goarch   string
+		fsys     fstest.MapFS
+		expected *resource.Resource
+	}{
+		{
+			"linux amd64",
+			"linux",
+			"amd64",
+			fstest.MapFS{
+				"etc/os-release": &fstest.MapFile{Data: b},
+			},
+			resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.HostArchAMD64,
+				semconv.OSTypeLinux,
+				semconv.OSName("Ubuntu"),
+			),
+		},
+		{
+			"linux arm64 fallback",
+			"linux",
+			"arm64",
+			fstest.MapFS{
+				"usr/lib/os-release": &fstest.MapFile{Data: []byte("NAME='Alpine Linux'\nID=alpine\n")},
+			},
+			resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.HostArchARM64,
+				semconv.OSTypeLinux,
+				semconv.OSName("Alpine Linux"),
+			),
+		},
+		{
+			"darwin arm64",
+			"darwin",
+			"arm64",
+			fstest.MapFS{},
+			resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.HostArchARM64,
+				semconv.OSTypeDarwin,
+			),
+		},
+		{
+			"linux ppc64le",
+			"linux",
+			"ppc64le",
+			fstest.MapFS{},
+			resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.HostArchPPC64,
+				semconv.OSTypeLinux,
+			),
+		},
+		{
+			"unknown",
+			"plan9",
+			"mips",
+			fstest.MapFS{},
+			resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			systemResourceDetector := resourceDetector{
+				goos:   table.goos,
+				goarch: table.goarch,
+				fsys:   table.fsys,
+			}
+
+			r, err := systemResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package systemd provides an OpenTelemetry detector for detecting systemd
+// service resources.
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	invocationIDEnv  = "INVOCATION_ID"
+	journalStreamEnv = "JOURNAL_STREAM"
+
+	cgroupFile = "proc/self/cgroup"
+
+	// hierarchy-ID:controller-list:cgroup-path
+	cgroupFields = 3
+)
+
+const (
+	invocationIDKey  = attribute.Key("systemd.invocation_id")
+	unitKey          = attribute.Key("systemd.unit")
+	journalStreamKey = attribute.Key("systemd.journal_stream")
+)
+
+//nolint:gochecknoglobals
+var unitSuffixes = []string{
+	".service",
+	".scope",
+	".socket",
+	".mount",
+	".swap",
+}
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type systemdDetectorUtils struct{}
+
+func (utils *systemdDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+	fsys  fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	invocationID, _ := detector.utils.lookupEnv(invocationIDEnv)
+	if invocationID == "" {
+		// Not a systemd unit
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		invocationIDKey.String(invocationID),
+	}
+
+	unit, err := detector.unit()
+	if err != nil {
+		return nil, err
+	}
+
+	if unit != "" {
+		attributes = append(attributes, unitKey.String(unit))
+	}
+
+	// Only set if the standard output or error is connected to the journal
+	if v, _ := detector.utils.lookupEnv(journalStreamEnv); v != "" {
+		attributes = append(attributes, journalStreamKey.String(v))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+// unit returns the name of the systemd unit from the cgroup of the process,
+// such as "0::/system.slice/foo.service" with cgroup v2 or
+// "1:name=systemd:/system.slice/foo.service" with cgroup v1.
+func (detector *resourceDetector) unit() (string, error) {
+	b, err := fs.ReadFile(detector.fsys, cgroupFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("error reading %s: %w", cgroupFile, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", cgroupFields)
+		if len(fields) != cgroupFields || (fields[1] != "" && fields[1] != "name=systemd") {
+			continue
+		}
+
+		return parseUnit(fields[2]), nil
+	}
+
+	return "", nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// parseUnit returns the innermost unit in the cgroup path, skipping any
+// slices, or any cgroups the unit has created itself if it has been delegated
+// a subtree. It returns an empty string if there is no unit.
+func parseUnit(cgroup string) string {
+	for dir := cgroup; dir != "/" && dir != "."; dir = path.Dir(dir) {
+		name := path.Base(dir)
+
+		for _, suffix := range unitSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read /proc/self/cgroup. It should be
+// rooted at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+func newResourceDetector(utils detectorUtils, options ...Option) *resourceDetector {
+	detector := &resourceDetector{
+		utils: utils,
+		fsys:  os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect systemd
+// service resources. The unit name is read from the cgroup of the process.
+// See the container/systemd package for detecting systemd-nspawn containers.
+func NewResourceDetector(options ...Option) resource.Detector {
+	return newResourceDetector(new(systemdDetectorUtils), options...)
+}
@@ -0,0 +1,147 @@
+package systemd
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const testInvocationID = "0b9f3c1e5e6d4a2f8b7c6d5e4f3a2b1c"
+
+var errTest = errors.New("test")
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+// errorFS returns an error when opening any file.
+type errorFS struct{}
+
+func (errorFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errTest}
+}
+
+func TestSystemd(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		name          string
+		cgroup        string
+		journalStream string
+		expected      []attribute.KeyValue
+	}{
+		{
+			name:          "cgroup v2",
+			cgroup:        "0::/system.slice/foo.service\n",
+			journalStream: "8:12345",
+			expected: []attribute.KeyValue{
+				unitKey.String("foo.service"),
+				journalStreamKey.String("8:12345"),
+			},
+		},
+		{
+			name:   "cgroup v1",
+			cgroup: "12:pids:/system.slice/foo.service\n11:cpu,cpuacct:/system.slice/foo.service\n1:name=systemd:/system.slice/foo.service\n",
+			expected: []attribute.KeyValue{
+				unitKey.String("foo.service"),
+			},
+		},
+		{
+			name:   "user service",
+			cgroup: "0::/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service\n",
+			expected: []attribute.KeyValue{
+				unitKey.String("foo.service"),
+			},
+		},
+		{
+			name:   "delegated",
+			cgroup: "0::/system.slice/foo.service/payload\n",
+			expected: []attribute.KeyValue{
+				unitKey.String("foo.service"),
+			},
+		},
+		{
+			name:   "scope",
+			cgroup: "0::/user.slice/user-1000.slice/session-2.scope\n",
+			expected: []attribute.KeyValue{
+				unitKey.String("session-2.scope"),
+			},
+		},
+		{
+			name:   "no unit",
+			cgroup: "0::/\n",
+		},
+		{
+			name: "no cgroup",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := fstest.MapFS{}
+			if table.cgroup != "" {
+				fsys["proc/self/cgroup"] = &fstest.MapFile{Data: []byte(table.cgroup)}
+			}
+
+			utils := new(mockDetectorUtils)
+			utils.On("lookupEnv", invocationIDEnv).Return(testInvocationID, true).Once()
+			utils.On("lookupEnv", journalStreamEnv).Return(table.journalStream, table.journalStream != "").Once()
+
+			systemdResourceDetector := newResourceDetector(utils, WithFS(fsys))
+
+			r, err := systemdResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, append([]attribute.KeyValue{
+				invocationIDKey.String(testInvocationID),
+			}, table.expected...)...), r)
+
+			utils.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotSystemd(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", invocationIDEnv).Return("", false).Once()
+
+	systemdResourceDetector := newResourceDetector(utils, WithFS(fstest.MapFS{}))
+
+	r, err := systemdResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestCgroupError(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", invocationIDEnv).Return(testInvocationID, true).Once()
+
+	systemdResourceDetector := newResourceDetector(utils, WithFS(errorFS{}))
+
+	r, err := systemdResourceDetector.Detect(t.Context())
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,85 @@
+// Package travis provides an OpenTelemetry detector for detecting Travis CI
+// job resources.
+package travis
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const (
+	buildIDEnv  = "TRAVIS_BUILD_ID"
+	jobIDEnv    = "TRAVIS_JOB_ID"
+	repoSlugEnv = "TRAVIS_REPO_SLUG"
+	commitEnv   = "TRAVIS_COMMIT"
+)
+
+type detectorUtils interface {
+	lookupEnv(key string) (string, bool)
+}
+
+type travisDetectorUtils struct{}
+
+func (utils *travisDetectorUtils) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type resourceDetector struct {
+	utils detectorUtils
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	buildID, _ := detector.utils.lookupEnv(buildIDEnv)
+	if buildID == "" {
+		// Not a Travis CI build
+		return resource.Empty(), nil
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.CICDPipelineRunID(buildID),
+	}
+
+	for _, s := range []struct {
+		env string
+		fn  func(string) attribute.KeyValue
+	}{
+		{
+			jobIDEnv,
+			semconv.CICDPipelineTaskRunID,
+		},
+		{
+			commitEnv,
+			semconv.VCSRefHeadRevision,
+		},
+	} {
+		if v, _ := detector.utils.lookupEnv(s.env); v != "" {
+			attributes = append(attributes, s.fn(v))
+		}
+	}
+
+	// Of the form "<owner>/<repository>"
+	if v, _ := detector.utils.lookupEnv(repoSlugEnv); v != "" {
+		if owner, name, ok := strings.Cut(v, "/"); ok {
+			attributes = append(attributes, semconv.VCSOwnerName(owner), semconv.VCSRepositoryName(name))
+		} else {
+			attributes = append(attributes, semconv.VCSRepositoryName(v))
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attributes...), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// NewResourceDetector returns a [resource.Detector] that will detect Travis CI
+// job resources.
+func NewResourceDetector() resource.Detector {
+	return &resourceDetector{
+		utils: new(travisDetectorUtils),
+	}
+}
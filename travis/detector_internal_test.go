@@ -0,0 +1,61 @@
+package travis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+type mockDetectorUtils struct {
+	mock.Mock
+}
+
+func (utils *mockDetectorUtils) lookupEnv(key string) (string, bool) {
+	args := utils.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func TestTravis(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("123456789", true).Once()
+	utils.On("lookupEnv", jobIDEnv).Return("987654321", true).Once()
+	utils.On("lookupEnv", commitEnv).Return("ffac537e6cbbf934b08745a378932722df287a53", true).Once()
+	utils.On("lookupEnv", repoSlugEnv).Return("travis-ci/travis-build", true).Once()
+
+	travisResourceDetector := resourceDetector{utils: utils}
+
+	r, err := travisResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewWithAttributes(semconv.SchemaURL, []attribute.KeyValue{
+		semconv.CICDPipelineRunID("123456789"),
+		semconv.CICDPipelineTaskRunID("987654321"),
+		semconv.VCSRefHeadRevision("ffac537e6cbbf934b08745a378932722df287a53"),
+		semconv.VCSOwnerName("travis-ci"),
+		semconv.VCSRepositoryName("travis-build"),
+	}...), r)
+
+	utils.AssertExpectations(t)
+}
+
+func TestNotTravis(t *testing.T) {
+	t.Parallel()
+
+	utils := new(mockDetectorUtils)
+	utils.On("lookupEnv", buildIDEnv).Return("", false).Once()
+
+	travisResourceDetector := resourceDetector{utils: utils}
+
+	r, err := travisResourceDetector.Detect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, resource.Empty(), r)
+
+	utils.AssertExpectations(t)
+}
@@ -0,0 +1,142 @@
+// Package vsphere provides an OpenTelemetry detector for detecting VMware
+// vSphere virtual machine resources.
+package vsphere
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const dmiDir = "sys/class/dmi/id"
+
+const (
+	sysVendorFile     = "sys_vendor"
+	productUUIDFile   = "product_uuid"
+	productSerialFile = "product_serial"
+)
+
+const (
+	vmwareVendor       = "VMware, Inc."
+	vmwareSerialPrefix = "VMware-"
+)
+
+// There's no semantic convention for this.
+const vmUUIDKey = attribute.Key("vsphere.vm.uuid")
+
+type resourceDetector struct {
+	fsys fs.FS
+}
+
+func (detector *resourceDetector) Detect(_ context.Context) (*resource.Resource, error) {
+	vendor, err := detector.readFile(sysVendorFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if vendor != vmwareVendor {
+		// Not a VMware virtual machine
+		return resource.Empty(), nil
+	}
+
+	uuid, err := detector.uuid()
+	if err != nil {
+		return nil, err
+	}
+
+	if uuid == "" {
+		// The UUID and serial number are usually only readable by root
+		return resource.Empty(), nil
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.HostID(uuid),
+		vmUUIDKey.String(uuid),
+	), nil
+}
+
+// uuid returns the BIOS UUID of the virtual machine, as shown by vSphere,
+// falling back to parsing it from the serial number.
+func (detector *resourceDetector) uuid() (string, error) {
+	uuid, err := detector.readFile(productUUIDFile)
+	if err != nil || uuid != "" {
+		return strings.ToLower(uuid), err
+	}
+
+	serial, err := detector.readFile(productSerialFile)
+	if err != nil {
+		return "", err
+	}
+
+	return parseSerial(serial), nil
+}
+
+func (detector *resourceDetector) readFile(file string) (string, error) {
+	name := path.Join(dmiDir, file)
+
+	b, err := fs.ReadFile(detector.fsys, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	return string(bytes.TrimSpace(b)), nil
+}
+
+var _ resource.Detector = new(resourceDetector)
+
+// parseSerial parses the UUID from a serial number such as
+// "VMware-42 1a 2b 3c 4d 5e 6f 70-81 92 a3 b4 c5 d6 e7 f8". It returns an
+// empty string if the serial number isn't in that format.
+func parseSerial(serial string) string {
+	hex, ok := strings.CutPrefix(serial, vmwareSerialPrefix)
+	if !ok {
+		return ""
+	}
+
+	hex = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(hex))
+	if len(hex) != 32 || strings.Trim(hex, "0123456789abcdef") != "" {
+		return ""
+	}
+
+	return hex[0:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:32]
+}
+
+// An Option configures the [resource.Detector] returned by
+// [NewResourceDetector].
+type Option func(*resourceDetector)
+
+// WithFS sets the filesystem used to read /sys/class/dmi/id. It should be
+// rooted at "/". The default is the real OS filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(detector *resourceDetector) {
+		detector.fsys = fsys
+	}
+}
+
+// NewResourceDetector returns a [resource.Detector] that will detect VMware
+// vSphere virtual machine resources from the SMBIOS/DMI information. The
+// UUID is usually only readable by root so nothing is detected otherwise.
+func NewResourceDetector(options ...Option) resource.Detector {
+	detector := &resourceDetector{
+		fsys: os.DirFS("/"),
+	}
+
+	for _, option := range options {
+		option(detector)
+	}
+
+	return detector
+}
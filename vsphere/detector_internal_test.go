@@ -0,0 +1,149 @@
+package vsphere
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+var errTest = errors.New("test")
+
+// errorFS returns the given error when reading the named files.
+type errorFS struct {
+	fstest.MapFS
+	errs map[string]error
+}
+
+func (fsys errorFS) Open(name string) (fs.File, error) {
+	if err, ok := fsys.errs[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return fsys.MapFS.Open(name)
+}
+
+func (fsys errorFS) ReadFile(name string) ([]byte, error) {
+	if err, ok := fsys.errs[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return fsys.MapFS.ReadFile(name)
+}
+
+func TestVSphere(t *testing.T) {
+	t.Parallel()
+
+	const serial = "VMware-42 1a 2b 3c 4d 5e 6f 70-81 92 a3 b4 c5 d6 e7 f8\n"
+
+	expected := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.HostID("421a2b3c-4d5e-6f70-8192-a3b4c5d6e7f8"),
+		vmUUIDKey.String("421a2b3c-4d5e-6f70-8192-a3b4c5d6e7f8"),
+	)
+
+	tables := []struct {
+		name     string
+		fsys     fs.FS
+		expected *resource.Resource
+	}{
+		{
+			name: "product uuid",
+			fsys: fstest.MapFS{
+				"sys/class/dmi/id/sys_vendor":     &fstest.MapFile{Data: []byte("VMware, Inc.\n")},
+				"sys/class/dmi/id/product_uuid":   &fstest.MapFile{Data: []byte("421A2B3C-4D5E-6F70-8192-A3B4C5D6E7F8\n")},
+				"sys/class/dmi/id/product_serial": &fstest.MapFile{Data: []byte(serial)},
+			},
+			expected: expected,
+		},
+		{
+			name: "product serial",
+			fsys: fstest.MapFS{
+				"sys/class/dmi/id/sys_vendor":     &fstest.MapFile{Data: []byte("VMware, Inc.\n")},
+				"sys/class/dmi/id/product_serial": &fstest.MapFile{Data: []byte(serial)},
+			},
+			expected: expected,
+		},
+		{
+			name: "unprivileged",
+			fsys: errorFS{
+				MapFS: fstest.MapFS{
+					"sys/class/dmi/id/sys_vendor": &fstest.MapFile{Data: []byte("VMware, Inc.\n")},
+				},
+				errs: map[string]error{
+					"sys/class/dmi/id/product_uuid":   fs.ErrPermission,
+					"sys/class/dmi/id/product_serial": fs.ErrPermission,
+				},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name: "not vmware",
+			fsys: fstest.MapFS{
+				"sys/class/dmi/id/sys_vendor":   &fstest.MapFile{Data: []byte("QEMU\n")},
+				"sys/class/dmi/id/product_uuid": &fstest.MapFile{Data: []byte("5d1e0a5e-1b2c-4d3e-8f4a-5b6c7d8e9f00\n")},
+			},
+			expected: resource.Empty(),
+		},
+		{
+			name:     "no dmi",
+			fsys:     fstest.MapFS{},
+			expected: resource.Empty(),
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			t.Parallel()
+
+			vsphereResourceDetector := NewResourceDetector(WithFS(table.fsys))
+
+			r, err := vsphereResourceDetector.Detect(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, r)
+		})
+	}
+}
+
+func TestParseSerial(t *testing.T) {
+	t.Parallel()
+
+	tables := []struct {
+		serial, expected string
+	}{
+		{"VMware-42 1a 2b 3c 4d 5e 6f 70-81 92 a3 b4 c5 d6 e7 f8", "421a2b3c-4d5e-6f70-8192-a3b4c5d6e7f8"},
+		{"VMware-42 1A 2B 3C 4D 5E 6F 70-81 92 A3 B4 C5 D6 E7 F8", "421a2b3c-4d5e-6f70-8192-a3b4c5d6e7f8"},
+		{"VMware-42 1a 2b 3c", ""},
+		{"VMware-zz 1a 2b 3c 4d 5e 6f 70-81 92 a3 b4 c5 d6 e7 f8", ""},
+		{"WM18AS001234", ""},
+	}
+
+	for _, table := range tables {
+		t.Run(table.serial, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, table.expected, parseSerial(table.serial))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	vsphereResourceDetector := NewResourceDetector(WithFS(errorFS{
+		MapFS: fstest.MapFS{
+			"sys/class/dmi/id/sys_vendor": &fstest.MapFile{Data: []byte("VMware, Inc.\n")},
+		},
+		errs: map[string]error{
+			"sys/class/dmi/id/product_uuid": errTest,
+		},
+	}))
+
+	r, err := vsphereResourceDetector.Detect(t.Context())
+	require.ErrorIs(t, err, errTest)
+	assert.Nil(t, r)
+}